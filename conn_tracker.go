@@ -0,0 +1,248 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/relychan/gohttps/listeners"
+	"github.com/relychan/goutils/httpheader"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ConnectionInfo represents an open connection of the server.
+type ConnectionInfo struct {
+	RemoteAddr string `json:"remoteAddr"`
+	LocalAddr  string `json:"localAddr"`
+	// The state of the connection: new, active or idle.
+	State string `json:"state"`
+	// The number of requests that are served on the connection.
+	Requests       int64     `json:"requests"`
+	CreatedAt      time.Time `json:"createdAt"`
+	StateChangedAt time.Time `json:"stateChangedAt"`
+}
+
+// trackedConn holds the state of a connection.
+type trackedConn struct {
+	remoteAddr     string
+	localAddr      string
+	state          http.ConnState
+	requests       int64
+	createdAt      time.Time
+	stateChangedAt time.Time
+}
+
+// connTracker keeps track of open connections of an HTTP server via the ConnState hook.
+// Hijacked connections are no longer tracked because the server is not notified when they are closed.
+type connTracker struct {
+	mu           sync.Mutex
+	conns        map[net.Conn]*trackedConn
+	metrics      *serverMetrics
+	registration metric.Registration
+}
+
+// newConnTracker creates a connection tracker. Connection metrics are recorded if metrics is not nil.
+func newConnTracker(metrics *serverMetrics) *connTracker {
+	return &connTracker{
+		conns:   map[net.Conn]*trackedConn{},
+		metrics: metrics,
+	}
+}
+
+// ConnState implements the http.Server.ConnState hook.
+func (ct *connTracker) ConnState(conn net.Conn, state http.ConnState) {
+	now := time.Now()
+	remoteAddr, localAddr := connAddrs(conn, state)
+
+	ct.mu.Lock()
+
+	tc, ok := ct.conns[conn]
+	if !ok {
+		tc = &trackedConn{createdAt: now}
+		ct.conns[conn] = tc
+	}
+
+	if remoteAddr != "" {
+		tc.remoteAddr = remoteAddr
+		tc.localAddr = localAddr
+	}
+
+	tc.state = state
+	tc.stateChangedAt = now
+
+	switch state {
+	case http.StateActive:
+		tc.requests++
+	case http.StateClosed, http.StateHijacked:
+		// Hijacked connections are no longer managed by the server.
+		delete(ct.conns, conn)
+	default:
+	}
+
+	ct.mu.Unlock()
+
+	if ct.metrics != nil && (state == http.StateClosed || state == http.StateHijacked) {
+		ct.recordConnection(tc, now)
+	}
+}
+
+// Count returns the number of open connections.
+func (ct *connTracker) Count() int {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	return len(ct.conns)
+}
+
+// Connections returns open connections in the order of creation.
+func (ct *connTracker) Connections() []ConnectionInfo {
+	ct.mu.Lock()
+
+	results := make([]ConnectionInfo, 0, len(ct.conns))
+
+	for _, tc := range ct.conns {
+		results = append(results, ConnectionInfo{
+			RemoteAddr:     tc.remoteAddr,
+			LocalAddr:      tc.localAddr,
+			State:          tc.state.String(),
+			Requests:       tc.requests,
+			CreatedAt:      tc.createdAt,
+			StateChangedAt: tc.stateChangedAt,
+		})
+	}
+
+	ct.mu.Unlock()
+
+	slices.SortFunc(results, func(a, b ConnectionInfo) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.RemoteAddr, b.RemoteAddr)
+	})
+
+	return results
+}
+
+// connAddrs returns the addresses of the connection to be recorded in the state.
+// New connections use the addresses of the underlying connection because reading the PROXY protocol header may block,
+// while active connections have read the header before the request.
+func connAddrs(conn net.Conn, state http.ConnState) (string, string) {
+	switch state {
+	case http.StateNew:
+		if tlsConn, ok := conn.(*tls.Conn); ok {
+			conn = tlsConn.NetConn()
+		}
+
+		if ppc, ok := conn.(*listeners.ProxyProtocolConn); ok {
+			conn = ppc.Conn
+		}
+	case http.StateActive:
+	default:
+		return "", ""
+	}
+
+	return conn.RemoteAddr().String(), conn.LocalAddr().String()
+}
+
+// countByState returns the number of open connections of every state.
+func (ct *connTracker) countByState() map[http.ConnState]int64 {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	results := map[http.ConnState]int64{
+		http.StateNew:    0,
+		http.StateActive: 0,
+		http.StateIdle:   0,
+	}
+
+	for _, tc := range ct.conns {
+		results[tc.state]++
+	}
+
+	return results
+}
+
+// observe registers the callback of the gauge of open connections by state. The gauge is observed until the tracker stops.
+func (ct *connTracker) observe() error {
+	registration, err := ct.metrics.meter.RegisterCallback(
+		func(_ context.Context, observer metric.Observer) error {
+			for state, count := range ct.countByState() {
+				observer.ObserveInt64(
+					ct.metrics.openConnections,
+					count,
+					metric.WithAttributes(attribute.String("state", state.String())),
+				)
+			}
+
+			return nil
+		},
+		ct.metrics.openConnections,
+	)
+	if err != nil {
+		return err
+	}
+
+	ct.registration = registration
+
+	return nil
+}
+
+// unregister stops observing the gauge of open connections.
+func (ct *connTracker) unregister() {
+	if ct.registration != nil {
+		_ = ct.registration.Unregister()
+		ct.registration = nil
+	}
+}
+
+// Run observes the open connections until the context is done.
+func (ct *connTracker) Run(ctx context.Context) {
+	<-ctx.Done()
+
+	ct.unregister()
+}
+
+// recordConnection records the lifetime and the number of requests of a connection that is closed or hijacked.
+func (ct *connTracker) recordConnection(tc *trackedConn, now time.Time) {
+	ctx := context.Background()
+	attrs := metric.WithAttributes(attribute.String("state", tc.state.String()))
+
+	ct.metrics.connectionDuration.Record(ctx, now.Sub(tc.createdAt).Seconds(), attrs)
+	ct.metrics.connectionRequests.Record(ctx, tc.requests, attrs)
+}
+
+// Handler returns the HTTP handler which lists open connections for debugging.
+func (ct *connTracker) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(httpheader.ContentType, httpheader.ContentTypeJSON)
+		w.Header().Set("Cache-Control", "no-store")
+
+		err := json.NewEncoder(w).Encode(ct.Connections())
+		if err != nil {
+			slog.Error("failed to write response: " + err.Error())
+		}
+	}
+}
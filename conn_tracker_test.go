@@ -0,0 +1,354 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/relychan/gohttps/listeners"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestConnTracker(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+
+	metrics, err := newServerMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatalf("failed to create metrics: %v", err)
+	}
+
+	tracker := newConnTracker(metrics)
+
+	if err := tracker.observe(); err != nil {
+		t.Fatalf("failed to observe metrics: %v", err)
+	}
+
+	first, firstPeer := net.Pipe()
+	defer firstPeer.Close()
+
+	second, secondPeer := net.Pipe()
+	defer secondPeer.Close()
+
+	tracker.ConnState(first, http.StateNew)
+	tracker.ConnState(first, http.StateActive)
+	tracker.ConnState(first, http.StateIdle)
+	tracker.ConnState(first, http.StateActive)
+	tracker.ConnState(second, http.StateNew)
+
+	t.Run("connections", func(t *testing.T) {
+		conns := tracker.Connections()
+		if len(conns) != 2 {
+			t.Fatalf("expected 2 connections, got %d", len(conns))
+		}
+
+		if conns[0].State != "active" || conns[0].Requests != 2 {
+			t.Errorf("expected an active connection with 2 requests, got %+v", conns[0])
+		}
+
+		if conns[1].State != "new" || conns[1].Requests != 0 {
+			t.Errorf("expected a new connection without requests, got %+v", conns[1])
+		}
+	})
+
+	t.Run("open gauge", func(t *testing.T) {
+		expected := map[string]int64{"new": 1, "active": 1, "idle": 0}
+		got := collectTestConnStateMetrics(t, reader).open
+
+		for state, count := range expected {
+			if got[state] != count {
+				t.Errorf("expected %d %s connections, got %d", count, state, got[state])
+			}
+		}
+	})
+
+	tracker.ConnState(first, http.StateClosed)
+	tracker.ConnState(second, http.StateHijacked)
+
+	t.Run("closed and hijacked", func(t *testing.T) {
+		if count := tracker.Count(); count != 0 {
+			t.Errorf("expected no connection, got %d", count)
+		}
+
+		result := collectTestConnStateMetrics(t, reader)
+
+		expected := map[string]int64{"closed": 2, "hijacked": 0}
+		for state, requests := range expected {
+			if result.requests[state] != requests {
+				t.Errorf("expected %d requests of %s connections, got %d", requests, state, result.requests[state])
+			}
+
+			if result.durations[state] != 1 {
+				t.Errorf("expected 1 lifetime of %s connections, got %d", state, result.durations[state])
+			}
+		}
+	})
+
+	t.Run("unregister once stopped", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		tracker.Run(ctx)
+
+		if got := collectTestConnStateMetrics(t, reader).open; len(got) != 0 {
+			t.Errorf("expected no data point, got %v", got)
+		}
+	})
+}
+
+func TestConnTrackerProxyProtocolPending(t *testing.T) {
+	rawListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer rawListener.Close()
+
+	listener, err := listeners.NewProxyProtocolListener(rawListener, listeners.ProxyProtocolConfig{})
+	if err != nil {
+		t.Fatalf("failed to create listener: %v", err)
+	}
+
+	client, err := net.Dial("tcp", rawListener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatalf("failed to accept: %v", err)
+	}
+	defer conn.Close()
+
+	tracker := newConnTracker(nil)
+	tracker.ConnState(conn, http.StateNew)
+
+	// The client has not sent the PROXY protocol header yet.
+	start := time.Now()
+	conns := tracker.Connections()
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the listing not to wait for the PROXY protocol header, took %s", elapsed)
+	}
+
+	if len(conns) != 1 || conns[0].RemoteAddr != client.LocalAddr().String() {
+		t.Errorf("expected the address of the underlying connection %s, got %+v", client.LocalAddr(), conns)
+	}
+}
+
+func TestConnTrackerHijacked(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+
+	metrics, err := newServerMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatalf("failed to create metrics: %v", err)
+	}
+
+	tracker := newConnTracker(metrics)
+
+	if err := tracker.observe(); err != nil {
+		t.Fatalf("failed to observe metrics: %v", err)
+	}
+
+	conn, peer := net.Pipe()
+	defer peer.Close()
+
+	tracker.ConnState(conn, http.StateNew)
+	tracker.ConnState(conn, http.StateActive)
+	tracker.ConnState(conn, http.StateHijacked)
+
+	if conns := tracker.Connections(); len(conns) != 0 {
+		t.Errorf("expected the hijacked connection not to be listed, got %+v", conns)
+	}
+
+	result := collectTestConnStateMetrics(t, reader)
+
+	for state, count := range result.open {
+		if count != 0 {
+			t.Errorf("expected the hijacked connection to be excluded from the open gauge, got %d %s", count, state)
+		}
+	}
+
+	if result.requests["hijacked"] != 1 || result.durations["hijacked"] != 1 {
+		t.Errorf("expected the hijacked connection to be recorded with 1 request, got %+v", result)
+	}
+
+	if _, ok := result.durations["closed"]; ok {
+		t.Errorf("expected no closed connection, got %+v", result.durations)
+	}
+}
+
+func TestServerObservesGaugesOnStart(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+
+	server := newTestServer(
+		t,
+		&ServerConfig{ConnectionLimit: &listeners.ConnectionLimitConfig{MaxConnections: 10}},
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+
+	// Servers that are never started must not register callbacks on the meter provider.
+	if got := collectTestGauges(t, reader); len(got) != 0 {
+		t.Fatalf("expected no gauge before the server starts, got %v", got)
+	}
+
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+
+	got := collectTestGauges(t, reader)
+
+	for _, name := range []string{"gohttps.connection.open", "gohttps.connection_limit.active"} {
+		if !slices.Contains(got, name) {
+			t.Errorf("expected the %s gauge once the server starts, got %v", name, got)
+		}
+	}
+}
+
+func collectTestGauges(t *testing.T, reader *sdkmetric.ManualReader) []string {
+	t.Helper()
+
+	var data metricdata.ResourceMetrics
+
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	var results []string
+
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if gauge, ok := m.Data.(metricdata.Gauge[int64]); ok && len(gauge.DataPoints) > 0 {
+				results = append(results, m.Name)
+			}
+		}
+	}
+
+	return results
+}
+
+func TestServerConnectionsEndpoint(t *testing.T) {
+	config := &ServerConfig{
+		ConnectionsEndpoint: "/debug/connections",
+	}
+
+	server := newTestServer(t, config)
+
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+
+	baseURL := "http://" + server.Addr().String()
+	transport := &http.Transport{}
+	client := &http.Client{Transport: transport, Timeout: 5 * time.Second}
+
+	defer transport.CloseIdleConnections()
+
+	for range 2 {
+		resp, err := client.Get(baseURL + "/test")
+		if err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	resp, err := client.Get(baseURL + config.ConnectionsEndpoint)
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var conns []ConnectionInfo
+
+	if err := json.NewDecoder(resp.Body).Decode(&conns); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(conns) != 1 {
+		t.Fatalf("expected 1 connection, got %+v", conns)
+	}
+
+	// The keep-alive connection serves the listing request as the third one.
+	if conns[0].State != "active" || conns[0].Requests != 3 {
+		t.Errorf("expected an active connection with 3 requests, got %+v", conns[0])
+	}
+
+	if conns[0].LocalAddr != server.Addr().String() {
+		t.Errorf("expected local address %s, got %s", server.Addr(), conns[0].LocalAddr)
+	}
+}
+
+type testConnStateMetrics struct {
+	open      map[string]int64
+	durations map[string]uint64
+	requests  map[string]int64
+}
+
+func collectTestConnStateMetrics(t *testing.T, reader *sdkmetric.ManualReader) testConnStateMetrics {
+	t.Helper()
+
+	var data metricdata.ResourceMetrics
+
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	result := testConnStateMetrics{
+		open:      map[string]int64{},
+		durations: map[string]uint64{},
+		requests:  map[string]int64{},
+	}
+
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				if m.Name != "gohttps.connection.open" {
+					continue
+				}
+
+				for _, point := range data.DataPoints {
+					state, _ := point.Attributes.Value("state")
+					result.open[state.AsString()] = point.Value
+				}
+			case metricdata.Histogram[float64]:
+				for _, point := range data.DataPoints {
+					state, _ := point.Attributes.Value("state")
+					result.durations[state.AsString()] = point.Count
+				}
+			case metricdata.Histogram[int64]:
+				for _, point := range data.DataPoints {
+					state, _ := point.Attributes.Value("state")
+					result.requests[state.AsString()] = point.Sum
+				}
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+
+	"github.com/relychan/gohttps/listeners"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// connectionLimiter enforces the connection limits on the listeners of the server and exports its metrics.
+type connectionLimiter struct {
+	*listeners.ConnectionLimiter
+
+	metrics      *serverMetrics
+	registration metric.Registration
+}
+
+// newConnectionLimiter creates the connection limiter of the server.
+func newConnectionLimiter(config listeners.ConnectionLimitConfig, metrics *serverMetrics) (*connectionLimiter, error) {
+	limiter, err := listeners.NewConnectionLimiter(config, func(reason listeners.ConnectionLimitReason) {
+		metrics.rejectedConnections.Add(
+			context.Background(),
+			1,
+			metric.WithAttributes(attribute.String("reason", string(reason))),
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &connectionLimiter{
+		ConnectionLimiter: limiter,
+		metrics:           metrics,
+	}, nil
+}
+
+// observe registers the callback of the gauge of active connections. The gauge is observed until the limiter stops.
+func (cl *connectionLimiter) observe() error {
+	registration, err := cl.metrics.meter.RegisterCallback(
+		func(_ context.Context, observer metric.Observer) error {
+			observer.ObserveInt64(cl.metrics.activeConnections, int64(cl.ActiveConnections()))
+
+			return nil
+		},
+		cl.metrics.activeConnections,
+	)
+	if err != nil {
+		return err
+	}
+
+	cl.registration = registration
+
+	return nil
+}
+
+// unregister stops observing the gauge of active connections.
+func (cl *connectionLimiter) unregister() {
+	if cl.registration != nil {
+		_ = cl.registration.Unregister()
+		cl.registration = nil
+	}
+}
+
+// Run observes the active connections until the context is done.
+func (cl *connectionLimiter) Run(ctx context.Context) {
+	<-ctx.Done()
+
+	cl.unregister()
+}
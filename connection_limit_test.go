@@ -0,0 +1,103 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/relychan/gohttps/listeners"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestServerConnectionLimit(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	config := &ServerConfig{
+		ConnectionLimit: &listeners.ConnectionLimitConfig{MaxConnectionsPerIP: 1},
+	}
+
+	server := newTestServer(
+		t,
+		config,
+		WithListener(listener),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+
+	url := "http://" + server.Addr().String() + "/test"
+
+	idleConn, err := net.Dial("tcp", server.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	resp, err := testHTTPClient.Get(url)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("expected the connection over the limit to be rejected")
+	}
+
+	var data metricdata.ResourceMetrics
+
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	var active, rejected int64
+
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch m.Name {
+			case "gohttps.connection_limit.active":
+				for _, point := range m.Data.(metricdata.Gauge[int64]).DataPoints {
+					active += point.Value
+				}
+			case "gohttps.connection_limit.rejected":
+				for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					reason, _ := point.Attributes.Value("reason")
+					if reason.AsString() != string(listeners.ConnectionLimitReasonMaxConnectionsPerIP) {
+						t.Errorf("expected reason %s, got %s", listeners.ConnectionLimitReasonMaxConnectionsPerIP, reason.AsString())
+					}
+
+					rejected += point.Value
+				}
+			}
+		}
+	}
+
+	if active != 1 {
+		t.Errorf("expected 1 active connection, got %d", active)
+	}
+
+	if rejected != 1 {
+		t.Errorf("expected 1 rejected connection, got %d", rejected)
+	}
+
+	_ = idleConn.Close()
+
+	waitForStatus(t, url, http.StatusOK)
+}
@@ -0,0 +1,238 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
+	"os"
+	"slices"
+	"time"
+)
+
+const (
+	devTLSCAValidity          = 10 * 365 * 24 * time.Hour
+	devTLSCertificateValidity = 365 * 24 * time.Hour
+)
+
+var (
+	errDevTLSInProduction = errors.New("development TLS must not be enabled in production")
+	errDevTLSCAInvalid    = errors.New("invalid development CA")
+)
+
+var defaultDevTLSHostnames = []string{"localhost", "127.0.0.1", "::1"}
+
+// DevTLSConfig represents the configuration of self-signed certificates for local development.
+type DevTLSConfig struct {
+	// Serve HTTPS with a certificate that is signed by a development CA generated on startup
+	// if no certificate files are configured. It is refused if the server runs in production.
+	Enabled bool `env:"SERVER_DEV_TLS_ENABLED" json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// Additional host names and IP addresses of the certificate. localhost, 127.0.0.1 and ::1 are always included.
+	Hostnames []string `env:"SERVER_DEV_TLS_HOSTNAMES" json:"hostnames,omitempty" yaml:"hostnames,omitempty"`
+	// The file to write the PEM-encoded CA certificate to, so browsers and tools can trust it, e.g. curl --cacert.
+	CACertFile string `env:"SERVER_DEV_TLS_CA_CERT_FILE" json:"caCertFile,omitempty" yaml:"caCertFile,omitempty"`
+	// The file of the PEM-encoded CA private key. If set with the CA certificate file, the CA is loaded from the files
+	// if they exist, otherwise it is written to them, so the CA only needs to be trusted once.
+	CAKeyFile string `env:"SERVER_DEV_TLS_CA_KEY_FILE" json:"caKeyFile,omitempty" yaml:"caKeyFile,omitempty"`
+}
+
+// GetHostnames returns host names and IP addresses of the certificate, including the default local addresses.
+func (dc DevTLSConfig) GetHostnames() []string {
+	results := slices.Clone(defaultDevTLSHostnames)
+
+	for _, hostname := range dc.Hostnames {
+		if hostname != "" && !slices.Contains(results, hostname) {
+			results = append(results, hostname)
+		}
+	}
+
+	return results
+}
+
+// useDevTLS checks if the server serves a development certificate, that is enabled and no certificate is configured.
+func useDevTLS(config *ServerConfig) bool {
+	return config.DevTLS != nil && config.DevTLS.Enabled && !hasTLSCertificates(config)
+}
+
+// newCertificate issues a certificate of the host names that is signed by the development CA.
+func (dc DevTLSConfig) newCertificate() (*tls.Certificate, error) {
+	caCert, caKey, err := dc.loadOrCreateCA()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	notAfter := now.Add(devTLSCertificateValidity)
+
+	// The certificate must not outlive the CA that is reused across restarts.
+	if notAfter.After(caCert.NotAfter) {
+		notAfter = caCert.NotAfter
+	}
+
+	template := &x509.Certificate{
+		Subject:     pkix.Name{Organization: []string{"gohttps development"}, CommonName: "localhost"},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	for _, hostname := range dc.GetHostnames() {
+		if ip := net.ParseIP(hostname); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, hostname)
+		}
+	}
+
+	der, err := createDevCertificate(template, caCert, key.Public(), caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Warn(
+		"serving TLS with a development certificate, do not use it in production",
+		slog.Any("hostnames", dc.GetHostnames()),
+		slog.String("ca_cert_file", dc.CACertFile),
+	)
+
+	return &tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, nil
+}
+
+// loadOrCreateCA loads the development CA from the CA files if both are set and exist.
+// Otherwise, a new CA is generated and written to the configured files.
+func (dc DevTLSConfig) loadOrCreateCA() (*x509.Certificate, crypto.Signer, error) {
+	if dc.CACertFile != "" && dc.CAKeyFile != "" {
+		_, err := os.Stat(dc.CACertFile)
+		if err == nil {
+			return loadDevCA(dc.CACertFile, dc.CAKeyFile)
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, nil, err
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+
+	template := &x509.Certificate{
+		Subject:               pkix.Name{Organization: []string{"gohttps development"}, CommonName: "gohttps development CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(devTLSCAValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	der, err := createDevCertificate(template, template, key.Public(), key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if dc.CACertFile != "" {
+		err = os.WriteFile(dc.CACertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644) //nolint:gosec
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if dc.CACertFile != "" && dc.CAKeyFile != "" {
+		keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = os.WriteFile(dc.CAKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return cert, key, nil
+}
+
+func loadDevCA(certFile string, keyFile string) (*x509.Certificate, crypto.Signer, error) {
+	keyPair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", errDevTLSCAInvalid, err)
+	}
+
+	if !keyPair.Leaf.IsCA {
+		return nil, nil, fmt.Errorf("%w: %s is not a CA certificate", errDevTLSCAInvalid, certFile)
+	}
+
+	if time.Now().After(keyPair.Leaf.NotAfter) {
+		return nil, nil, fmt.Errorf("%w: %s is expired", errDevTLSCAInvalid, certFile)
+	}
+
+	signer, ok := keyPair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: unsupported private key", errDevTLSCAInvalid)
+	}
+
+	return keyPair.Leaf, signer, nil
+}
+
+func createDevCertificate(
+	template *x509.Certificate,
+	parent *x509.Certificate,
+	publicKey crypto.PublicKey,
+	signer crypto.Signer,
+) ([]byte, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	template.SerialNumber = serialNumber
+
+	return x509.CreateCertificate(rand.Reader, template, parent, publicKey, signer)
+}
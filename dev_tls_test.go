@@ -0,0 +1,199 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestServerDevTLS(t *testing.T) {
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+
+	config := &ServerConfig{
+		DevTLS: &DevTLSConfig{
+			Enabled:    true,
+			Hostnames:  []string{"dev.example.test", "localhost"},
+			CACertFile: caCertFile,
+		},
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	server, err := NewServer(config, NewRouter(config, slog.Default()), WithListener(listener))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = server.Shutdown(context.Background())
+	})
+
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+
+	caPool := loadTestCertPool(t, caCertFile)
+
+	for _, serverName := range []string{"localhost", "127.0.0.1", "dev.example.test"} {
+		t.Run(serverName, func(t *testing.T) {
+			client := &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig:   &tls.Config{RootCAs: caPool, ServerName: serverName},
+					DisableKeepAlives: true,
+				},
+			}
+
+			resp, err := client.Get("https://" + server.Addr().String() + pathHealthz)
+			if err != nil {
+				t.Fatalf("failed to send request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("expected status 200, got %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServerDevTLS_Production(t *testing.T) {
+	config := &ServerConfig{
+		Production: true,
+		DevTLS:     &DevTLSConfig{Enabled: true},
+	}
+
+	_, err := NewServer(config, NewRouter(config, slog.Default()))
+	if !errors.Is(err, errDevTLSInProduction) {
+		t.Errorf("expected errDevTLSInProduction, got %v", err)
+	}
+}
+
+func TestUseDevTLS(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   ServerConfig
+		expected bool
+	}{
+		{
+			name:   "disabled",
+			config: ServerConfig{DevTLS: &DevTLSConfig{}},
+		},
+		{
+			name:     "enabled",
+			config:   ServerConfig{DevTLS: &DevTLSConfig{Enabled: true}},
+			expected: true,
+		},
+		{
+			name: "certificate files take precedence",
+			config: ServerConfig{
+				TLSCertFile: "cert.pem",
+				TLSKeyFile:  "key.pem",
+				DevTLS:      &DevTLSConfig{Enabled: true},
+			},
+		},
+		{
+			name: "certificate directory takes precedence",
+			config: ServerConfig{
+				TLS:    &TLSConfig{CertificateDir: "certs"},
+				DevTLS: &DevTLSConfig{Enabled: true},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := useDevTLS(&tc.config); result != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestDevTLSConfig_ReuseCA(t *testing.T) {
+	dir := t.TempDir()
+	config := DevTLSConfig{
+		Enabled:    true,
+		CACertFile: filepath.Join(dir, "ca.pem"),
+		CAKeyFile:  filepath.Join(dir, "ca-key.pem"),
+	}
+
+	first, err := config.newCertificate()
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	caPEM, err := os.ReadFile(config.CACertFile)
+	if err != nil {
+		t.Fatalf("failed to read CA file: %v", err)
+	}
+
+	second, err := config.newCertificate()
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	reloadedCAPEM, err := os.ReadFile(config.CACertFile)
+	if err != nil {
+		t.Fatalf("failed to read CA file: %v", err)
+	}
+
+	if !bytes.Equal(caPEM, reloadedCAPEM) {
+		t.Error("expected the CA to be reused")
+	}
+
+	caPool := loadTestCertPool(t, config.CACertFile)
+
+	for _, cert := range []*tls.Certificate{first, second} {
+		_, err := cert.Leaf.Verify(x509.VerifyOptions{Roots: caPool, DNSName: "localhost"})
+		if err != nil {
+			t.Errorf("failed to verify certificate: %v", err)
+		}
+	}
+
+	t.Run("invalid CA", func(t *testing.T) {
+		if err := os.WriteFile(config.CAKeyFile, []byte("invalid"), 0o600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		_, err := config.newCertificate()
+		if !errors.Is(err, errDevTLSCAInvalid) {
+			t.Errorf("expected errDevTLSCAInvalid, got %v", err)
+		}
+	})
+}
+
+func loadTestCertPool(t *testing.T, certFile string) *x509.CertPool {
+	t.Helper()
+
+	pool, err := loadCertPool(certFile)
+	if err != nil {
+		t.Fatalf("failed to load CA file: %v", err)
+	}
+
+	return pool
+}
@@ -42,9 +42,6 @@ func main() {
 	defer ts.Shutdown(context.TODO())
 
 	router := gohttps.NewRouter(serverConfig, ts.Logger)
-	router.Use(gotel.NewTracingMiddleware(ts, gotel.ResponseWriterWrapperFunc(func(w http.ResponseWriter, protoMajor int) gotel.WrapResponseWriter {
-		return middleware.NewWrapResponseWriter(w, protoMajor)
-	})))
 	router.Use(middleware.AllowContentType("application/json"))
 
 	router.Get("/hello", func(w http.ResponseWriter, r *http.Request) {
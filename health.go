@@ -0,0 +1,332 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/relychan/goutils/httpheader"
+)
+
+const (
+	contentTypeHealthJSON     = "application/health+json"
+	defaultHealthCheckTimeout = 5 * time.Second
+)
+
+var (
+	// ErrHealthCheckDegraded can be wrapped by health checkers to report a degraded (warn) state
+	// instead of a failure. Degraded checks do not make the probe fail.
+	ErrHealthCheckDegraded = errors.New("degraded")
+
+	errHealthCheckNameRequired    = errors.New("health check name is required")
+	errHealthCheckCheckerRequired = errors.New("health checker is required")
+	errHealthCheckDuplicated      = errors.New("health check is already registered")
+	errServerNotStarted           = errors.New("server is not started")
+	errServerDraining             = errors.New("server is shutting down")
+)
+
+// HealthChecker abstracts a health check of a dependency, e.g. a database, cache or downstream service.
+type HealthChecker interface {
+	// CheckHealth returns a non-nil error if the dependency is unhealthy.
+	CheckHealth(ctx context.Context) error
+}
+
+// HealthCheckFunc is an adapter to allow the use of ordinary functions as health checkers.
+type HealthCheckFunc func(ctx context.Context) error
+
+// CheckHealth calls f(ctx).
+func (f HealthCheckFunc) CheckHealth(ctx context.Context) error {
+	return f(ctx)
+}
+
+// HealthProbe represents the enum of health probe types.
+type HealthProbe string
+
+const (
+	// HealthProbeLiveness checks whether the process is alive. Served at /livez.
+	HealthProbeLiveness HealthProbe = "liveness"
+	// HealthProbeReadiness checks whether the server is ready to accept traffic. Served at /readyz.
+	HealthProbeReadiness HealthProbe = "readiness"
+	// HealthProbeStartup checks whether the server has finished starting up. Served at /startupz.
+	HealthProbeStartup HealthProbe = "startup"
+)
+
+// HealthStatus represents the enum of health statuses of the IETF health check response format.
+type HealthStatus string
+
+const (
+	// HealthStatusPass indicates a healthy status.
+	HealthStatusPass HealthStatus = "pass"
+	// HealthStatusWarn indicates a healthy status with some concerns (degraded).
+	HealthStatusWarn HealthStatus = "warn"
+	// HealthStatusFail indicates an unhealthy status.
+	HealthStatusFail HealthStatus = "fail"
+)
+
+// HealthCheckOptions holds the options of a registered health check.
+type HealthCheckOptions struct {
+	// The probes that the check belongs to. Default is readiness only.
+	Probes []HealthProbe
+	// The maximum duration of a single check. Default is 5 seconds.
+	Timeout time.Duration
+	// The duration to cache the check result. The check runs on every probe if zero.
+	CacheTTL time.Duration
+	// Failures of optional checks are reported as warn instead of fail.
+	Optional bool
+	// The type of the component, e.g. datastore, component or system.
+	ComponentType string
+}
+
+// HealthCheckResult represents the result of a health check in the IETF health check response format.
+type HealthCheckResult struct {
+	ComponentType string       `json:"componentType,omitempty"`
+	ObservedValue float64      `json:"observedValue"`
+	ObservedUnit  string       `json:"observedUnit"`
+	Status        HealthStatus `json:"status"`
+	Time          time.Time    `json:"time"`
+	Output        string       `json:"output,omitempty"`
+}
+
+// HealthReport represents the health check response in the IETF health check response format.
+// See https://datatracker.ietf.org/doc/html/draft-inadarei-api-health-check.
+type HealthReport struct {
+	Status HealthStatus                   `json:"status"`
+	Output string                         `json:"output,omitempty"`
+	Checks map[string][]HealthCheckResult `json:"checks,omitempty"`
+}
+
+// HealthRegistry holds named health checks and serves the liveness, readiness and startup probes.
+type HealthRegistry struct {
+	mu       sync.RWMutex
+	checks   []*registeredHealthCheck
+	started  atomic.Bool
+	draining atomic.Bool
+}
+
+// NewHealthRegistry creates an empty health registry.
+func NewHealthRegistry() *HealthRegistry {
+	return &HealthRegistry{}
+}
+
+// Register adds a named health check to the registry.
+func (hr *HealthRegistry) Register(
+	name string,
+	checker HealthChecker,
+	options HealthCheckOptions,
+) error {
+	if name == "" {
+		return errHealthCheckNameRequired
+	}
+
+	if checker == nil {
+		return errHealthCheckCheckerRequired
+	}
+
+	if len(options.Probes) == 0 {
+		options.Probes = []HealthProbe{HealthProbeReadiness}
+	}
+
+	if options.Timeout <= 0 {
+		options.Timeout = defaultHealthCheckTimeout
+	}
+
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	for _, check := range hr.checks {
+		if check.name == name {
+			return fmt.Errorf("%w: %s", errHealthCheckDuplicated, name)
+		}
+	}
+
+	hr.checks = append(hr.checks, &registeredHealthCheck{
+		name:    name,
+		checker: checker,
+		options: options,
+	})
+
+	return nil
+}
+
+// Check runs all health checks of the probe concurrently and aggregates results.
+func (hr *HealthRegistry) Check(ctx context.Context, probe HealthProbe) HealthReport {
+	report := HealthReport{
+		Status: HealthStatusPass,
+	}
+
+	switch {
+	case probe == HealthProbeReadiness && hr.draining.Load():
+		report.Status = HealthStatusFail
+		report.Output = errServerDraining.Error()
+	case probe != HealthProbeLiveness && !hr.started.Load():
+		report.Status = HealthStatusFail
+		report.Output = errServerNotStarted.Error()
+	}
+
+	hr.mu.RLock()
+
+	checks := make([]*registeredHealthCheck, 0, len(hr.checks))
+
+	for _, check := range hr.checks {
+		if slices.Contains(check.options.Probes, probe) {
+			checks = append(checks, check)
+		}
+	}
+
+	hr.mu.RUnlock()
+
+	if len(checks) == 0 {
+		return report
+	}
+
+	results := make([]HealthCheckResult, len(checks))
+
+	var wg sync.WaitGroup
+
+	for i, check := range checks {
+		wg.Go(func() {
+			results[i] = check.run(ctx)
+		})
+	}
+
+	wg.Wait()
+
+	report.Checks = make(map[string][]HealthCheckResult, len(checks))
+
+	for i, check := range checks {
+		result := results[i]
+		report.Checks[check.name+":responseTime"] = []HealthCheckResult{result}
+
+		switch {
+		case result.Status == HealthStatusFail:
+			report.Status = HealthStatusFail
+		case result.Status == HealthStatusWarn && report.Status == HealthStatusPass:
+			report.Status = HealthStatusWarn
+		}
+	}
+
+	return report
+}
+
+// Handler returns the HTTP handler which serves the health report of the probe.
+// Responds with a 503 Service Unavailable status if the probe fails.
+func (hr *HealthRegistry) Handler(probe HealthProbe) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := hr.Check(r.Context(), probe)
+		statusCode := http.StatusOK
+
+		if report.Status == HealthStatusFail {
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set(httpheader.ContentType, contentTypeHealthJSON)
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(statusCode)
+
+		err := json.NewEncoder(w).Encode(report)
+		if err != nil {
+			slog.Error("failed to write response: " + err.Error())
+		}
+	}
+}
+
+// Ready reports whether the server is started and not shutting down.
+func (hr *HealthRegistry) Ready() bool {
+	return hr.started.Load() && !hr.draining.Load()
+}
+
+func (hr *HealthRegistry) setStarted(value bool) {
+	hr.started.Store(value)
+}
+
+func (hr *HealthRegistry) setDraining(value bool) {
+	hr.draining.Store(value)
+}
+
+type registeredHealthCheck struct {
+	name    string
+	checker HealthChecker
+	options HealthCheckOptions
+
+	mu       sync.Mutex
+	cached   *HealthCheckResult
+	cachedAt time.Time
+}
+
+func (rhc *registeredHealthCheck) run(ctx context.Context) HealthCheckResult {
+	if rhc.options.CacheTTL > 0 {
+		rhc.mu.Lock()
+		defer rhc.mu.Unlock()
+
+		if rhc.cached != nil && time.Since(rhc.cachedAt) < rhc.options.CacheTTL {
+			return *rhc.cached
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, rhc.options.Timeout)
+	defer cancel()
+
+	startTime := time.Now()
+	errCh := make(chan error, 1)
+
+	// Run the check in a goroutine so checkers that ignore the context cannot exceed the timeout.
+	go func() {
+		errCh <- rhc.checker.CheckHealth(ctx)
+	}()
+
+	var err error
+
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	latency := time.Since(startTime)
+
+	result := HealthCheckResult{
+		ComponentType: rhc.options.ComponentType,
+		ObservedValue: float64(latency.Microseconds()) / 1000,
+		ObservedUnit:  "ms",
+		Status:        HealthStatusPass,
+		Time:          startTime.UTC(),
+	}
+
+	if err != nil {
+		result.Output = err.Error()
+
+		if rhc.options.Optional || errors.Is(err, ErrHealthCheckDegraded) {
+			result.Status = HealthStatusWarn
+		} else {
+			result.Status = HealthStatusFail
+		}
+	}
+
+	if rhc.options.CacheTTL > 0 {
+		rhc.cached = &result
+		rhc.cachedAt = startTime
+	}
+
+	return result
+}
@@ -0,0 +1,207 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHealthRegistry_Register(t *testing.T) {
+	registry := NewHealthRegistry()
+	checker := HealthCheckFunc(func(context.Context) error { return nil })
+
+	if err := registry.Register("", checker, HealthCheckOptions{}); !errors.Is(err, errHealthCheckNameRequired) {
+		t.Errorf("expected errHealthCheckNameRequired, got %v", err)
+	}
+
+	if err := registry.Register("db", nil, HealthCheckOptions{}); !errors.Is(err, errHealthCheckCheckerRequired) {
+		t.Errorf("expected errHealthCheckCheckerRequired, got %v", err)
+	}
+
+	if err := registry.Register("db", checker, HealthCheckOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := registry.Register("db", checker, HealthCheckOptions{}); !errors.Is(err, errHealthCheckDuplicated) {
+		t.Errorf("expected errHealthCheckDuplicated, got %v", err)
+	}
+}
+
+func TestHealthRegistry_Check(t *testing.T) {
+	newRegistry := func(t *testing.T) *HealthRegistry {
+		t.Helper()
+
+		registry := NewHealthRegistry()
+		registry.setStarted(true)
+
+		return registry
+	}
+
+	t.Run("pass without checks", func(t *testing.T) {
+		report := newRegistry(t).Check(context.Background(), HealthProbeReadiness)
+		if report.Status != HealthStatusPass {
+			t.Errorf("expected pass, got %s", report.Status)
+		}
+	})
+
+	t.Run("fail before started", func(t *testing.T) {
+		registry := NewHealthRegistry()
+
+		if report := registry.Check(context.Background(), HealthProbeStartup); report.Status != HealthStatusFail {
+			t.Errorf("expected fail, got %s", report.Status)
+		}
+
+		if report := registry.Check(context.Background(), HealthProbeLiveness); report.Status != HealthStatusPass {
+			t.Errorf("expected liveness to pass, got %s", report.Status)
+		}
+	})
+
+	t.Run("readiness fails while draining", func(t *testing.T) {
+		registry := newRegistry(t)
+		registry.setDraining(true)
+
+		if report := registry.Check(context.Background(), HealthProbeReadiness); report.Status != HealthStatusFail {
+			t.Errorf("expected fail, got %s", report.Status)
+		}
+
+		if report := registry.Check(context.Background(), HealthProbeStartup); report.Status != HealthStatusPass {
+			t.Errorf("expected startup to pass, got %s", report.Status)
+		}
+	})
+
+	t.Run("aggregates statuses", func(t *testing.T) {
+		registry := newRegistry(t)
+		_ = registry.Register("db", HealthCheckFunc(func(context.Context) error {
+			return nil
+		}), HealthCheckOptions{ComponentType: "datastore"})
+		_ = registry.Register("cache", HealthCheckFunc(func(context.Context) error {
+			return fmt.Errorf("%w: high latency", ErrHealthCheckDegraded)
+		}), HealthCheckOptions{})
+		_ = registry.Register("upstream", HealthCheckFunc(func(context.Context) error {
+			return errors.New("connection refused")
+		}), HealthCheckOptions{Probes: []HealthProbe{HealthProbeLiveness}})
+
+		report := registry.Check(context.Background(), HealthProbeReadiness)
+		if report.Status != HealthStatusWarn {
+			t.Errorf("expected warn, got %s", report.Status)
+		}
+
+		if len(report.Checks) != 2 {
+			t.Fatalf("expected 2 checks, got %d", len(report.Checks))
+		}
+
+		db := report.Checks["db:responseTime"]
+		if len(db) != 1 || db[0].Status != HealthStatusPass || db[0].ComponentType != "datastore" {
+			t.Errorf("unexpected db result: %+v", db)
+		}
+
+		report = registry.Check(context.Background(), HealthProbeLiveness)
+		if report.Status != HealthStatusFail {
+			t.Errorf("expected fail, got %s", report.Status)
+		}
+
+		if output := report.Checks["upstream:responseTime"][0].Output; output != "connection refused" {
+			t.Errorf("unexpected output: %s", output)
+		}
+	})
+
+	t.Run("optional check reports warn", func(t *testing.T) {
+		registry := newRegistry(t)
+		_ = registry.Register("search", HealthCheckFunc(func(context.Context) error {
+			return errors.New("unavailable")
+		}), HealthCheckOptions{Optional: true})
+
+		if report := registry.Check(context.Background(), HealthProbeReadiness); report.Status != HealthStatusWarn {
+			t.Errorf("expected warn, got %s", report.Status)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		registry := newRegistry(t)
+		_ = registry.Register("slow", HealthCheckFunc(func(context.Context) error {
+			time.Sleep(time.Second)
+
+			return nil
+		}), HealthCheckOptions{Timeout: 50 * time.Millisecond})
+
+		startTime := time.Now()
+		report := registry.Check(context.Background(), HealthProbeReadiness)
+
+		if time.Since(startTime) > 500*time.Millisecond {
+			t.Error("expected the check to time out")
+		}
+
+		if report.Status != HealthStatusFail {
+			t.Errorf("expected fail, got %s", report.Status)
+		}
+	})
+
+	t.Run("cache", func(t *testing.T) {
+		var calls atomic.Int32
+
+		registry := newRegistry(t)
+		_ = registry.Register("db", HealthCheckFunc(func(context.Context) error {
+			calls.Add(1)
+
+			return nil
+		}), HealthCheckOptions{CacheTTL: time.Minute})
+
+		for range 3 {
+			registry.Check(context.Background(), HealthProbeReadiness)
+		}
+
+		if calls.Load() != 1 {
+			t.Errorf("expected 1 call, got %d", calls.Load())
+		}
+	})
+}
+
+func TestHealthRegistry_Handler(t *testing.T) {
+	registry := NewHealthRegistry()
+	registry.setStarted(true)
+
+	_ = registry.Register("db", HealthCheckFunc(func(context.Context) error {
+		return errors.New("down")
+	}), HealthCheckOptions{})
+
+	req := httptest.NewRequest(http.MethodGet, pathReadyz, nil)
+	w := httptest.NewRecorder()
+	registry.Handler(HealthProbeReadiness).ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+
+	if contentType := w.Header().Get("Content-Type"); contentType != contentTypeHealthJSON {
+		t.Errorf("expected content type %s, got %s", contentTypeHealthJSON, contentType)
+	}
+
+	var report HealthReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if report.Status != HealthStatusFail {
+		t.Errorf("expected fail, got %s", report.Status)
+	}
+}
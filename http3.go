@@ -0,0 +1,236 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/relychan/goutils"
+)
+
+const (
+	http3HealthCheckName = "http3"
+	// The name of the UDP socket of HTTP/3 when it is handed off to another process.
+	http3ListenerName = "http3"
+	// The duration that clients may cache the advertised HTTP/3 endpoint. Same as the default of quic-go.
+	http3AltSvcMaxAge = 30 * 24 * time.Hour
+)
+
+var (
+	errHTTP3RequiresTLS   = errors.New("HTTP/3 requires TLS")
+	errHTTP3ConfigInvalid = errors.New("invalid HTTP/3 config")
+	errHTTP3NotServing    = errors.New("HTTP/3 server is not serving")
+)
+
+// HTTP3Config represents the configuration of the HTTP/3 server that serves the router over QUIC on UDP.
+// The UDP socket is handed off with the TCP listeners during binary upgrades.
+// QUIC connections of the old process may be interrupted while both processes read from the socket, then clients reconnect.
+type HTTP3Config struct {
+	// Serve HTTP/3 next to the TCP listeners. Require TLS.
+	Enabled bool `env:"SERVER_HTTP3_ENABLED" json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// The UDP address to listen on. Default is the port of the server on all interfaces.
+	Address string `env:"SERVER_HTTP3_ADDRESS" json:"address,omitempty" yaml:"address,omitempty"`
+	// The port that is advertised in the Alt-Svc header of responses over TCP,
+	// e.g. the public port of a load balancer. Default is the port of the UDP listener.
+	AdvertisedPort int `env:"SERVER_HTTP3_ADVERTISED_PORT" json:"advertisedPort,omitempty" yaml:"advertisedPort,omitempty" jsonschema:"minimum=0,maximum=65535"`
+	// The duration after which an idle QUIC connection is closed. Default is 30 seconds.
+	MaxIdleTimeout goutils.Duration `env:"SERVER_HTTP3_MAX_IDLE_TIMEOUT" json:"maxIdleTimeout,omitempty" yaml:"maxIdleTimeout,omitempty"`
+	// The maximum number of concurrent request streams that a client may open per connection. Default is 100.
+	MaxIncomingStreams int64 `env:"SERVER_HTTP3_MAX_INCOMING_STREAMS" json:"maxIncomingStreams,omitempty" yaml:"maxIncomingStreams,omitempty" jsonschema:"minimum=0"`
+	// Accept 0-RTT requests on resumed connections. Requests sent in 0-RTT can be replayed by attackers.
+	Allow0RTT bool `env:"SERVER_HTTP3_ALLOW_0RTT" json:"allow0rtt,omitempty" yaml:"allow0rtt,omitempty"`
+}
+
+// Validate checks if the configuration is valid.
+func (hc HTTP3Config) Validate() error {
+	switch {
+	case hc.AdvertisedPort < 0 || hc.AdvertisedPort > 65535:
+		return fmt.Errorf("%w: advertisedPort must be between 0 and 65535", errHTTP3ConfigInvalid)
+	case hc.MaxIdleTimeout < 0:
+		return fmt.Errorf("%w: maxIdleTimeout must not be negative", errHTTP3ConfigInvalid)
+	case hc.MaxIncomingStreams < 0:
+		return fmt.Errorf("%w: maxIncomingStreams must not be negative", errHTTP3ConfigInvalid)
+	}
+
+	return nil
+}
+
+// GetAddress returns the UDP address to listen on. Default is the port of the server on all interfaces.
+func (hc HTTP3Config) GetAddress(port int) string {
+	if hc.Address != "" {
+		return hc.Address
+	}
+
+	return ":" + strconv.Itoa(port)
+}
+
+// isHTTP3Enabled checks if the server serves HTTP/3.
+func isHTTP3Enabled(config *ServerConfig) bool {
+	return config.HTTP3 != nil && config.HTTP3.Enabled
+}
+
+// http3Listener serves the router over HTTP/3 on a UDP socket.
+type http3Listener struct {
+	config  HTTP3Config
+	server  *http3.Server
+	conn    net.PacketConn
+	altSvc  string
+	serving atomic.Bool
+}
+
+// newHTTP3Listener creates the HTTP/3 server that shares the handler and the TLS config of the TCP server.
+func newHTTP3Listener(
+	config HTTP3Config,
+	handler http.Handler,
+	tlsConfig *tls.Config,
+	maxHeaderBytes int,
+	idleTimeout time.Duration,
+) *http3Listener {
+	return &http3Listener{
+		config: config,
+		server: &http3.Server{
+			Handler: handler,
+			// quic-go clones the TLS config when serving. Resolve it on every handshake instead,
+			// so reloaded certificates and rotated session ticket keys apply to HTTP/3 as well.
+			TLSConfig: &tls.Config{
+				MinVersion: tls.VersionTLS13,
+				GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+					return tlsConfig, nil
+				},
+			},
+			QUICConfig: &quic.Config{
+				MaxIdleTimeout:     time.Duration(config.MaxIdleTimeout),
+				MaxIncomingStreams: config.MaxIncomingStreams,
+				Allow0RTT:          config.Allow0RTT,
+			},
+			MaxHeaderBytes: maxHeaderBytes,
+			IdleTimeout:    idleTimeout,
+		},
+	}
+}
+
+// listen binds the UDP socket and prepares the Alt-Svc header that advertises it.
+// The inherited socket, e.g. handed off by the parent process during an upgrade, is served instead if not nil.
+func (hl *http3Listener) listen(
+	ctx context.Context,
+	listenConfig *net.ListenConfig,
+	port int,
+	inherited net.PacketConn,
+) error {
+	conn := inherited
+
+	if conn == nil {
+		var err error
+
+		conn, err = listenConfig.ListenPacket(ctx, "udp", hl.config.GetAddress(port))
+		if err != nil {
+			return err
+		}
+	} else {
+		slog.Info("Inherited the HTTP/3 socket on " + conn.LocalAddr().String())
+	}
+
+	advertisedPort := hl.config.AdvertisedPort
+
+	if advertisedPort == 0 {
+		if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok {
+			advertisedPort = addr.Port
+		}
+	}
+
+	hl.conn = conn
+	hl.altSvc = fmt.Sprintf(`%s=":%d"; ma=%d`, http3.NextProtoH3, advertisedPort, int(http3AltSvcMaxAge.Seconds()))
+	hl.serving.Store(true)
+
+	return nil
+}
+
+// serve serves HTTP/3 requests until the server is shut down.
+func (hl *http3Listener) serve() error {
+	defer hl.serving.Store(false)
+
+	slog.Info("Listening HTTP/3 server on " + hl.conn.LocalAddr().String())
+
+	err := hl.server.Serve(hl.conn)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+// shutdown gracefully shuts down the HTTP/3 server and closes the UDP socket.
+// Connections are closed once the context is done.
+func (hl *http3Listener) shutdown(ctx context.Context) error {
+	err := hl.server.Shutdown(ctx)
+
+	if hl.conn != nil {
+		closeErr := hl.conn.Close()
+		if closeErr != nil && !errors.Is(closeErr, net.ErrClosed) {
+			err = errors.Join(err, closeErr)
+		}
+	}
+
+	return err
+}
+
+// close closes the HTTP/3 server and the UDP socket immediately.
+func (hl *http3Listener) close() {
+	_ = hl.server.Close()
+
+	if hl.conn != nil {
+		_ = hl.conn.Close()
+	}
+}
+
+// addr returns the address of the UDP socket. Returns nil if the socket is not bound.
+func (hl *http3Listener) addr() net.Addr {
+	if hl.conn == nil {
+		return nil
+	}
+
+	return hl.conn.LocalAddr()
+}
+
+// CheckHealth returns an error if the HTTP/3 server is not serving.
+func (hl *http3Listener) CheckHealth(_ context.Context) error {
+	if !hl.serving.Load() {
+		return errHTTP3NotServing
+	}
+
+	return nil
+}
+
+// advertise sets the Alt-Svc header on responses over TCP so clients can switch to HTTP/3.
+func (hl *http3Listener) advertise(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor < 3 && hl.serving.Load() {
+			w.Header().Add("Alt-Svc", hl.altSvc)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
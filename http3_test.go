@@ -0,0 +1,253 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+	"github.com/relychan/goutils"
+)
+
+func TestServerHTTP3(t *testing.T) {
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+
+	config := &ServerConfig{
+		DevTLS: &DevTLSConfig{
+			Enabled:    true,
+			CACertFile: caCertFile,
+		},
+		HTTP3: &HTTP3Config{
+			Enabled: true,
+			Address: "127.0.0.1:0",
+		},
+	}
+
+	server := newTestServer(t, config)
+
+	if server.HTTP3Addr() != nil {
+		t.Errorf("expected no HTTP/3 address before the server starts, got %s", server.HTTP3Addr())
+	}
+
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+
+	tlsClientConfig := &tls.Config{
+		RootCAs:    loadTestCertPool(t, caCertFile),
+		ServerName: "localhost",
+	}
+
+	h3Transport := &http3.Transport{TLSClientConfig: tlsClientConfig}
+	defer h3Transport.Close()
+
+	h3Client := &http.Client{Transport: h3Transport, Timeout: 5 * time.Second}
+	h3URL := "https://" + server.HTTP3Addr().String() + "/test"
+
+	t.Run("serve", func(t *testing.T) {
+		resp, err := h3Client.Get(h3URL)
+		if err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected status 200, got %d", resp.StatusCode)
+		}
+
+		if resp.ProtoMajor != 3 {
+			t.Errorf("expected HTTP/3, got %s", resp.Proto)
+		}
+
+		if altSvc := resp.Header.Get("Alt-Svc"); altSvc != "" {
+			t.Errorf("expected no Alt-Svc header over HTTP/3, got %s", altSvc)
+		}
+	})
+
+	t.Run("alt_svc", func(t *testing.T) {
+		client := &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig:   tlsClientConfig,
+				DisableKeepAlives: true,
+			},
+		}
+
+		resp, err := client.Get("https://" + server.Addr().String() + "/test")
+		if err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		port := server.HTTP3Addr().(*net.UDPAddr).Port
+		expected := fmt.Sprintf(`h3=":%d"; ma=2592000`, port)
+
+		if altSvc := resp.Header.Get("Alt-Svc"); altSvc != expected {
+			t.Errorf("expected Alt-Svc header %s, got %s", expected, altSvc)
+		}
+	})
+
+	t.Run("health", func(t *testing.T) {
+		report := server.healthRegistry.Check(context.Background(), HealthProbeReadiness)
+
+		if report.Status != HealthStatusPass {
+			t.Errorf("expected status pass, got %s: %+v", report.Status, report.Checks)
+		}
+
+		if _, ok := report.Checks[http3HealthCheckName+":responseTime"]; !ok {
+			t.Errorf("expected the HTTP/3 health check, got %+v", report.Checks)
+		}
+	})
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("failed to shutdown server: %v", err)
+	}
+
+	t.Run("shutdown", func(t *testing.T) {
+		if err := server.http3.CheckHealth(context.Background()); !errors.Is(err, errHTTP3NotServing) {
+			t.Errorf("expected errHTTP3NotServing, got %v", err)
+		}
+
+		client := &http.Client{
+			Transport: &http3.Transport{TLSClientConfig: tlsClientConfig},
+			Timeout:   time.Second,
+		}
+
+		resp, err := client.Get(h3URL)
+		if err == nil {
+			resp.Body.Close()
+			t.Error("expected the request to fail after shutdown")
+		}
+	})
+}
+
+func TestServerHTTP3_AdvertisedPort(t *testing.T) {
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+
+	config := &ServerConfig{
+		DevTLS: &DevTLSConfig{
+			Enabled:    true,
+			CACertFile: caCertFile,
+		},
+		HTTP3: &HTTP3Config{
+			Enabled:        true,
+			Address:        "127.0.0.1:0",
+			AdvertisedPort: 443,
+		},
+	}
+
+	server := newTestServer(t, config)
+
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{RootCAs: loadTestCertPool(t, caCertFile)},
+			DisableKeepAlives: true,
+		},
+	}
+
+	resp, err := client.Get("https://" + server.Addr().String() + "/test")
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	expected := `h3=":443"; ma=2592000`
+
+	if altSvc := resp.Header.Get("Alt-Svc"); altSvc != expected {
+		t.Errorf("expected Alt-Svc header %s, got %s", expected, altSvc)
+	}
+}
+
+func TestServerHTTP3_RequiresTLS(t *testing.T) {
+	config := &ServerConfig{
+		HTTP3: &HTTP3Config{Enabled: true},
+	}
+
+	_, err := NewServer(config, NewRouter(config, slog.Default()))
+	if !errors.Is(err, errHTTP3RequiresTLS) {
+		t.Errorf("expected errHTTP3RequiresTLS, got %v", err)
+	}
+}
+
+func TestHTTP3Config_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config HTTP3Config
+		valid  bool
+	}{
+		{
+			name:   "empty",
+			config: HTTP3Config{},
+			valid:  true,
+		},
+		{
+			name: "valid",
+			config: HTTP3Config{
+				AdvertisedPort:     443,
+				MaxIdleTimeout:     goutils.Duration(time.Minute),
+				MaxIncomingStreams: 200,
+			},
+			valid: true,
+		},
+		{
+			name:   "advertised_port_out_of_range",
+			config: HTTP3Config{AdvertisedPort: 65536},
+		},
+		{
+			name:   "negative_max_idle_timeout",
+			config: HTTP3Config{MaxIdleTimeout: goutils.Duration(-time.Second)},
+		},
+		{
+			name:   "negative_max_incoming_streams",
+			config: HTTP3Config{MaxIncomingStreams: -1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+
+			if tc.valid && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+
+			if !tc.valid && !errors.Is(err, errHTTP3ConfigInvalid) {
+				t.Errorf("expected errHTTP3ConfigInvalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestHTTP3Config_GetAddress(t *testing.T) {
+	if address := (HTTP3Config{}).GetAddress(8443); address != ":8443" {
+		t.Errorf("expected :8443, got %s", address)
+	}
+
+	if address := (HTTP3Config{Address: "127.0.0.1:443"}).GetAddress(8443); address != "127.0.0.1:443" {
+		t.Errorf("expected 127.0.0.1:443, got %s", address)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/relychan/goutils"
+)
+
+const (
+	http2MinReadFrameSize        = 16 * kilobyte
+	http2MaxReadFrameSize        = 16 * kilobyte * kilobyte
+	http2MaxHeaderTableSize      = 4 * kilobyte * kilobyte
+	http2MinReceiveBufferPerConn = 64 * kilobyte
+	http2MaxReceiveBuffer        = 4 * kilobyte * kilobyte
+)
+
+var (
+	errHTTPProtocolInvalid       = errors.New("invalid HTTP protocol")
+	errHTTPProtocolsNotSupported = errors.New("no configured HTTP protocol is supported by the server")
+	errHTTP2ConfigInvalid        = errors.New("invalid HTTP/2 config")
+)
+
+// HTTPProtocol represents an HTTP protocol that the server accepts.
+type HTTPProtocol string
+
+const (
+	// HTTPProtocolHTTP1 accepts HTTP/1.1 over cleartext and TLS connections.
+	HTTPProtocolHTTP1 HTTPProtocol = "http1"
+	// HTTPProtocolHTTP2 accepts HTTP/2 over TLS connections.
+	HTTPProtocolHTTP2 HTTPProtocol = "http2"
+	// HTTPProtocolUnencryptedHTTP2 accepts HTTP/2 over cleartext connections (h2c) with prior knowledge,
+	// e.g. from a service mesh proxy.
+	HTTPProtocolUnencryptedHTTP2 HTTPProtocol = "h2c"
+)
+
+// newHTTPProtocols parses the protocols that the server accepts. Default is HTTP/1 and HTTP/2 over TLS.
+func newHTTPProtocols(names []HTTPProtocol) (http.Protocols, error) {
+	var protocols http.Protocols
+
+	if len(names) == 0 {
+		protocols.SetHTTP1(true)
+		protocols.SetHTTP2(true)
+
+		return protocols, nil
+	}
+
+	for _, name := range names {
+		switch name {
+		case HTTPProtocolHTTP1:
+			protocols.SetHTTP1(true)
+		case HTTPProtocolHTTP2:
+			protocols.SetHTTP2(true)
+		case HTTPProtocolUnencryptedHTTP2:
+			protocols.SetUnencryptedHTTP2(true)
+		default:
+			return protocols, fmt.Errorf("%w: %s", errHTTPProtocolInvalid, name)
+		}
+	}
+
+	return protocols, nil
+}
+
+// validateHTTPProtocols checks if the server accepts any protocol over its connections.
+func validateHTTPProtocols(protocols http.Protocols, tlsEnabled bool) error {
+	if tlsEnabled && !protocols.HTTP1() && !protocols.HTTP2() {
+		return fmt.Errorf("%w: TLS connections require http1 or http2", errHTTPProtocolsNotSupported)
+	}
+
+	if !tlsEnabled && !protocols.HTTP1() && !protocols.UnencryptedHTTP2() {
+		return fmt.Errorf("%w: cleartext connections require http1 or h2c", errHTTPProtocolsNotSupported)
+	}
+
+	return nil
+}
+
+// adjustNextProtos adds the ALPN protocols of the enabled HTTP protocols if missing and removes the disabled ones.
+func adjustNextProtos(nextProtos []string, protocols http.Protocols) []string {
+	nextProtos = slices.DeleteFunc(slices.Clone(nextProtos), func(protocol string) bool {
+		return (protocol == "h2" && !protocols.HTTP2()) || (protocol == "http/1.1" && !protocols.HTTP1())
+	})
+
+	if protocols.HTTP2() && !slices.Contains(nextProtos, "h2") {
+		nextProtos = append(nextProtos, "h2")
+	}
+
+	if protocols.HTTP1() && !slices.Contains(nextProtos, "http/1.1") {
+		nextProtos = append(nextProtos, "http/1.1")
+	}
+
+	return nextProtos
+}
+
+// HTTP2Config represents the HTTP/2 configuration of the server. Zero values use the defaults of Go.
+type HTTP2Config struct {
+	// The number of concurrent streams that a client may have open at a time. Default is at least 100.
+	MaxConcurrentStreams int `env:"SERVER_HTTP2_MAX_CONCURRENT_STREAMS" json:"maxConcurrentStreams,omitempty" yaml:"maxConcurrentStreams,omitempty" jsonschema:"minimum=0"`
+	// The upper limit in bytes for the size of the header compression table used for decoding headers sent by the client.
+	// Must be less than 4MiB.
+	MaxDecoderHeaderTableSize int `env:"SERVER_HTTP2_MAX_DECODER_HEADER_TABLE_SIZE" json:"maxDecoderHeaderTableSize,omitempty" yaml:"maxDecoderHeaderTableSize,omitempty" jsonschema:"minimum=0,exclusiveMaximum=4194304"`
+	// The upper limit in bytes for the header compression table used for sending headers to the client.
+	// Must be less than 4MiB.
+	MaxEncoderHeaderTableSize int `env:"SERVER_HTTP2_MAX_ENCODER_HEADER_TABLE_SIZE" json:"maxEncoderHeaderTableSize,omitempty" yaml:"maxEncoderHeaderTableSize,omitempty" jsonschema:"minimum=0,exclusiveMaximum=4194304"`
+	// The largest frame in bytes that the server is willing to read. Must be between 16KiB and 16MiB.
+	MaxReadFrameSize int `env:"SERVER_HTTP2_MAX_READ_FRAME_SIZE" json:"maxReadFrameSize,omitempty" yaml:"maxReadFrameSize,omitempty" jsonschema:"minimum=0,maximum=16777216"`
+	// The maximum size in bytes of the flow control window for data received on a connection.
+	// Must be at least 64KiB and less than 4MiB.
+	MaxReceiveBufferPerConnection int `env:"SERVER_HTTP2_MAX_RECEIVE_BUFFER_PER_CONNECTION" json:"maxReceiveBufferPerConnection,omitempty" yaml:"maxReceiveBufferPerConnection,omitempty" jsonschema:"minimum=0,exclusiveMaximum=4194304"`
+	// The maximum size in bytes of the flow control window for data received on a stream. Must be less than 4MiB.
+	MaxReceiveBufferPerStream int `env:"SERVER_HTTP2_MAX_RECEIVE_BUFFER_PER_STREAM" json:"maxReceiveBufferPerStream,omitempty" yaml:"maxReceiveBufferPerStream,omitempty" jsonschema:"minimum=0,exclusiveMaximum=4194304"`
+	// The duration after which a health check using a ping frame is carried out if no frame is received on a connection.
+	// If zero, no health check is performed.
+	SendPingTimeout goutils.Duration `env:"SERVER_HTTP2_SEND_PING_TIMEOUT" json:"sendPingTimeout,omitempty" yaml:"sendPingTimeout,omitempty"`
+	// The duration after which a connection is closed if a response to a ping is not received. Default is 15 seconds.
+	PingTimeout goutils.Duration `env:"SERVER_HTTP2_PING_TIMEOUT" json:"pingTimeout,omitempty" yaml:"pingTimeout,omitempty"`
+	// The duration after which a connection is closed if no data can be written to it.
+	// The timeout begins when data is available to write, and is extended whenever any bytes are written.
+	WriteByteTimeout goutils.Duration `env:"SERVER_HTTP2_WRITE_BYTE_TIMEOUT" json:"writeByteTimeout,omitempty" yaml:"writeByteTimeout,omitempty"`
+	// Permit cipher suites that are prohibited by the HTTP/2 spec.
+	PermitProhibitedCipherSuites bool `env:"SERVER_HTTP2_PERMIT_PROHIBITED_CIPHER_SUITES" json:"permitProhibitedCipherSuites,omitempty" yaml:"permitProhibitedCipherSuites,omitempty"`
+}
+
+// Validate checks if the configuration is valid. Go silently ignores invalid values otherwise.
+func (hc HTTP2Config) Validate() error {
+	switch {
+	case hc.MaxConcurrentStreams < 0:
+		return fmt.Errorf("%w: maxConcurrentStreams must not be negative", errHTTP2ConfigInvalid)
+	case hc.MaxDecoderHeaderTableSize < 0 || hc.MaxDecoderHeaderTableSize >= http2MaxHeaderTableSize:
+		return fmt.Errorf("%w: maxDecoderHeaderTableSize must be less than 4MiB", errHTTP2ConfigInvalid)
+	case hc.MaxEncoderHeaderTableSize < 0 || hc.MaxEncoderHeaderTableSize >= http2MaxHeaderTableSize:
+		return fmt.Errorf("%w: maxEncoderHeaderTableSize must be less than 4MiB", errHTTP2ConfigInvalid)
+	case hc.MaxReadFrameSize != 0 &&
+		(hc.MaxReadFrameSize < http2MinReadFrameSize || hc.MaxReadFrameSize > http2MaxReadFrameSize):
+		return fmt.Errorf("%w: maxReadFrameSize must be between 16KiB and 16MiB", errHTTP2ConfigInvalid)
+	case hc.MaxReceiveBufferPerConnection != 0 &&
+		(hc.MaxReceiveBufferPerConnection < http2MinReceiveBufferPerConn ||
+			hc.MaxReceiveBufferPerConnection >= http2MaxReceiveBuffer):
+		return fmt.Errorf("%w: maxReceiveBufferPerConnection must be at least 64KiB and less than 4MiB", errHTTP2ConfigInvalid)
+	case hc.MaxReceiveBufferPerStream < 0 || hc.MaxReceiveBufferPerStream >= http2MaxReceiveBuffer:
+		return fmt.Errorf("%w: maxReceiveBufferPerStream must be less than 4MiB", errHTTP2ConfigInvalid)
+	}
+
+	return nil
+}
+
+// toHTTP2Config converts the configuration to the HTTP/2 config of the HTTP server.
+func (hc HTTP2Config) toHTTP2Config() *http.HTTP2Config {
+	return &http.HTTP2Config{
+		MaxConcurrentStreams:          hc.MaxConcurrentStreams,
+		MaxDecoderHeaderTableSize:     hc.MaxDecoderHeaderTableSize,
+		MaxEncoderHeaderTableSize:     hc.MaxEncoderHeaderTableSize,
+		MaxReadFrameSize:              hc.MaxReadFrameSize,
+		MaxReceiveBufferPerConnection: hc.MaxReceiveBufferPerConnection,
+		MaxReceiveBufferPerStream:     hc.MaxReceiveBufferPerStream,
+		SendPingTimeout:               time.Duration(hc.SendPingTimeout),
+		PingTimeout:                   time.Duration(hc.PingTimeout),
+		WriteByteTimeout:              time.Duration(hc.WriteByteTimeout),
+		PermitProhibitedCipherSuites:  hc.PermitProhibitedCipherSuites,
+	}
+}
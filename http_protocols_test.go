@@ -0,0 +1,211 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/relychan/goutils"
+)
+
+func TestNewHTTPProtocols(t *testing.T) {
+	tests := []struct {
+		name        string
+		protocols   []HTTPProtocol
+		http1       bool
+		http2       bool
+		unencrypted bool
+		wantErr     error
+	}{
+		{
+			name:  "default",
+			http1: true,
+			http2: true,
+		},
+		{
+			name:      "HTTP/1 only",
+			protocols: []HTTPProtocol{HTTPProtocolHTTP1},
+			http1:     true,
+		},
+		{
+			name:        "h2c",
+			protocols:   []HTTPProtocol{HTTPProtocolHTTP1, HTTPProtocolUnencryptedHTTP2},
+			http1:       true,
+			unencrypted: true,
+		},
+		{
+			name:      "invalid",
+			protocols: []HTTPProtocol{"http3"},
+			wantErr:   errHTTPProtocolInvalid,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			protocols, err := newHTTPProtocols(tc.protocols)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Errorf("expected %v, got %v", tc.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if protocols.HTTP1() != tc.http1 || protocols.HTTP2() != tc.http2 || protocols.UnencryptedHTTP2() != tc.unencrypted {
+				t.Errorf("unexpected protocols: %s", protocols)
+			}
+		})
+	}
+}
+
+func TestHTTP2Config_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  HTTP2Config
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			config: HTTP2Config{
+				MaxConcurrentStreams:          250,
+				MaxReadFrameSize:              1 << 20,
+				MaxReceiveBufferPerConnection: 1 << 20,
+				MaxReceiveBufferPerStream:     1 << 20,
+				PingTimeout:                   goutils.Duration(5 * time.Second),
+			},
+		},
+		{
+			name:    "negative concurrent streams",
+			config:  HTTP2Config{MaxConcurrentStreams: -1},
+			wantErr: true,
+		},
+		{
+			name:    "read frame size too small",
+			config:  HTTP2Config{MaxReadFrameSize: 1024},
+			wantErr: true,
+		},
+		{
+			name:    "read frame size too large",
+			config:  HTTP2Config{MaxReadFrameSize: 32 << 20},
+			wantErr: true,
+		},
+		{
+			name:    "connection receive buffer too small",
+			config:  HTTP2Config{MaxReceiveBufferPerConnection: 1024},
+			wantErr: true,
+		},
+		{
+			name:    "header table too large",
+			config:  HTTP2Config{MaxDecoderHeaderTableSize: 4 << 20},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.wantErr != errors.Is(err, errHTTP2ConfigInvalid) {
+				t.Errorf("expected error %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAdjustNextProtos(t *testing.T) {
+	var http1Only http.Protocols
+	http1Only.SetHTTP1(true)
+
+	result := adjustNextProtos([]string{"acme-tls/1", "h2"}, http1Only)
+	if len(result) != 2 || result[0] != "acme-tls/1" || result[1] != "http/1.1" {
+		t.Errorf("unexpected ALPN protocols: %v", result)
+	}
+}
+
+func TestServerProtocols(t *testing.T) {
+	t.Run("h2c", func(t *testing.T) {
+		server := newTestServer(t, &ServerConfig{
+			Protocols: []HTTPProtocol{HTTPProtocolHTTP1, HTTPProtocolUnencryptedHTTP2},
+			HTTP2:     &HTTP2Config{MaxConcurrentStreams: 10},
+		})
+
+		if err := server.Start(context.Background()); err != nil {
+			t.Fatalf("failed to start server: %v", err)
+		}
+
+		var protocols http.Protocols
+		protocols.SetUnencryptedHTTP2(true)
+
+		client := &http.Client{Transport: &http.Transport{Protocols: &protocols}}
+		defer client.CloseIdleConnections()
+
+		resp, err := client.Get("http://" + server.Addr().String() + "/test")
+		if err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.ProtoMajor != 2 {
+			t.Errorf("expected HTTP/2, got %s", resp.Proto)
+		}
+	})
+
+	t.Run("HTTP/1 only over TLS", func(t *testing.T) {
+		ca := newTestCertificateAuthority(t)
+		certFile, keyFile := ca.writeCertificate(t, ca.issue(t, "localhost", nil))
+
+		server := newTestServer(t, &ServerConfig{
+			Protocols:   []HTTPProtocol{HTTPProtocolHTTP1},
+			TLSCertFile: certFile,
+			TLSKeyFile:  keyFile,
+		})
+
+		if err := server.Start(context.Background()); err != nil {
+			t.Fatalf("failed to start server: %v", err)
+		}
+
+		conn, err := tls.Dial("tcp", server.Addr().String(), &tls.Config{
+			RootCAs:    ca.pool,
+			ServerName: "localhost",
+			NextProtos: []string{"h2", "http/1.1"},
+		})
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer conn.Close()
+
+		if protocol := conn.ConnectionState().NegotiatedProtocol; protocol != "http/1.1" {
+			t.Errorf("expected http/1.1, got %s", protocol)
+		}
+	})
+
+	t.Run("unsupported protocols", func(t *testing.T) {
+		config := &ServerConfig{Protocols: []HTTPProtocol{HTTPProtocolHTTP2}}
+
+		_, err := NewServer(config, NewRouter(config, slog.Default()))
+		if !errors.Is(err, errHTTPProtocolsNotSupported) {
+			t.Errorf("expected errHTTPProtocolsNotSupported, got %v", err)
+		}
+	})
+}
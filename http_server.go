@@ -0,0 +1,723 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/relychan/gohttps/listeners"
+)
+
+// Server manages the lifecycle of the HTTP server and the optional Prometheus server.
+type Server struct {
+	config            *ServerConfig
+	options           *serverOptions
+	healthRegistry    *HealthRegistry
+	httpServer        *http.Server
+	tracker           *connTracker
+	promServer        *http.Server
+	promTracker       *connTracker
+	metrics           *serverMetrics
+	tlsConfig         *tls.Config
+	certReloader      *certificateReloader
+	expiryMonitor     *certificateExpiryMonitor
+	sessionTicketKeys *sessionTicketKeyManager
+	http3             *http3Listener
+	connectionLimiter *connectionLimiter
+
+	mu           sync.Mutex
+	started      bool
+	listeners    []namedListener
+	promListener net.Listener
+	upgradeMu    sync.Mutex
+	upgraded     bool
+	// cancels background tasks, e.g. certificate reloading, when the server stops.
+	cancelBackground context.CancelFunc
+	done             chan struct{}
+	doneOnce         sync.Once
+	err              error
+}
+
+// NewServer creates a new server from the config and router.
+// The health check endpoints are registered to the router.
+func NewServer(config *ServerConfig, router *chi.Mux, options ...ServerOption) (*Server, error) {
+	if config == nil {
+		return nil, errServerConfigRequired
+	}
+
+	for _, address := range config.Addresses {
+		err := address.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.MinDataRate != nil {
+		err := config.MinDataRate.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.RequestID != nil {
+		err := config.RequestID.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Tracing != nil {
+		err := config.Tracing.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.AccessLog != nil {
+		err := config.AccessLog.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Listener != nil {
+		err := config.Listener.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.ConnectionLimit != nil {
+		err := config.ConnectionLimit.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.ProxyProtocol != nil {
+		err := config.ProxyProtocol.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Production && config.DevTLS != nil && config.DevTLS.Enabled {
+		return nil, errDevTLSInProduction
+	}
+
+	if config.HTTP2 != nil {
+		err := config.HTTP2.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if isHTTP3Enabled(config) {
+		err := config.HTTP3.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	protocols, err := newHTTPProtocols(config.Protocols)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateHTTPProtocols(protocols, tlsConfig != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if isHTTP3Enabled(config) && tlsConfig == nil {
+		return nil, errHTTP3RequiresTLS
+	}
+
+	opts := newServerOptions(options)
+
+	metrics, err := newServerMetrics(opts.meterProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	registerHealthRoutes(router, opts.healthRegistry)
+
+	// setup prometheus handler if enabled
+	promServer, err := CreatePrometheusServer(router, config.GetPort())
+	if err != nil {
+		return nil, err
+	}
+
+	maxHeaderBytes := http.DefaultMaxHeaderBytes
+
+	if config.MaxHeaderKilobytes > 0 {
+		maxHeaderBytes = config.MaxHeaderKilobytes * kilobyte
+	}
+
+	server := &Server{
+		config:         config,
+		options:        opts,
+		healthRegistry: opts.healthRegistry,
+		tracker:        newConnTracker(metrics),
+		promServer:     promServer,
+		metrics:        metrics,
+		tlsConfig:      tlsConfig,
+		done:           make(chan struct{}),
+	}
+
+	if config.ConnectionsEndpoint != "" {
+		router.Get(config.ConnectionsEndpoint, server.tracker.Handler())
+	}
+
+	if tlsConfig != nil {
+		err = server.setupCertificates(tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		if config.TLS != nil {
+			server.sessionTicketKeys, err = newSessionTicketKeyManager(tlsConfig, *config.TLS)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if config.ConnectionLimit != nil && config.ConnectionLimit.IsEnabled() {
+		server.connectionLimiter, err = newConnectionLimiter(*config.ConnectionLimit, metrics)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	server.httpServer = &http.Server{
+		ConnState:         server.tracker.ConnState,
+		Handler:           router,
+		TLSConfig:         tlsConfig,
+		ReadTimeout:       time.Duration(config.ReadTimeout),
+		ReadHeaderTimeout: time.Duration(config.ReadHeaderTimeout),
+		WriteTimeout:      time.Duration(config.WriteTimeout),
+		IdleTimeout:       time.Duration(config.IdleTimeout),
+		MaxHeaderBytes:    maxHeaderBytes,
+	}
+
+	if len(config.Protocols) > 0 {
+		server.httpServer.Protocols = &protocols
+	}
+
+	if config.HTTP2 != nil {
+		server.httpServer.HTTP2 = config.HTTP2.toHTTP2Config()
+	}
+
+	if config.ProxyProtocol != nil {
+		server.httpServer.ConnContext = listeners.WithProxyHeaderContext
+	}
+
+	if isHTTP3Enabled(config) {
+		server.http3 = newHTTP3Listener(
+			*config.HTTP3,
+			router,
+			tlsConfig,
+			maxHeaderBytes,
+			time.Duration(config.IdleTimeout),
+		)
+		server.httpServer.Handler = server.http3.advertise(router)
+
+		err = opts.healthRegistry.Register(http3HealthCheckName, server.http3, HealthCheckOptions{
+			Optional:      true,
+			ComponentType: "component",
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if promServer != nil {
+		server.promTracker = newConnTracker(nil)
+		promServer.ConnState = server.promTracker.ConnState
+	}
+
+	return server, nil
+}
+
+// observeMetrics registers the callbacks of the gauges. They are unregistered when the background tasks stop.
+func (s *Server) observeMetrics() error {
+	err := s.tracker.observe()
+
+	if err == nil && s.expiryMonitor != nil {
+		err = s.expiryMonitor.observe()
+	}
+
+	if err == nil && s.connectionLimiter != nil {
+		err = s.connectionLimiter.observe()
+	}
+
+	if err != nil {
+		s.tracker.unregister()
+
+		if s.expiryMonitor != nil {
+			s.expiryMonitor.unregister()
+		}
+
+		if s.connectionLimiter != nil {
+			s.connectionLimiter.unregister()
+		}
+	}
+
+	return err
+}
+
+// setupCertificates sets the certificates that the TLS config serves and monitors their expiry.
+func (s *Server) setupCertificates(tlsConfig *tls.Config) error {
+	if useDevTLS(s.config) {
+		devCertificate, err := s.config.DevTLS.newCertificate()
+		if err != nil {
+			return err
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{*devCertificate}
+	} else {
+		certReloader, err := newCertificateReloader(s.config, s.metrics)
+		if err != nil {
+			return err
+		}
+
+		s.certReloader = certReloader
+		tlsConfig.GetCertificate = certReloader.GetCertificate
+	}
+
+	var thresholds []time.Duration
+
+	if s.config.TLS != nil {
+		thresholds = s.config.TLS.GetExpiryWarningThresholds()
+	} else {
+		thresholds = TLSConfig{}.GetExpiryWarningThresholds()
+	}
+
+	s.expiryMonitor = newCertificateExpiryMonitor(s.servedCertificates, thresholds, s.metrics)
+	// Warn about certificates that are about to expire on startup.
+	s.expiryMonitor.check(time.Now())
+
+	return nil
+}
+
+// servedCertificates returns the certificates that the server currently serves.
+func (s *Server) servedCertificates() []*tls.Certificate {
+	if s.certReloader != nil {
+		return s.certReloader.store.Load().certificates
+	}
+
+	results := make([]*tls.Certificate, len(s.tlsConfig.Certificates))
+
+	for i := range s.tlsConfig.Certificates {
+		results[i] = &s.tlsConfig.Certificates[i]
+	}
+
+	return results
+}
+
+// Start binds the listeners and serves requests in the background. It returns once the server is listening.
+// The context is the base context of incoming requests. Its cancellation does not stop the server, call Shutdown instead.
+func (s *Server) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return errServerAlreadyStarted
+	}
+
+	listenerConfig := s.config.GetListenerConfig()
+	listenConfig := listenerConfig.newListenConfig()
+
+	inherited, inheritedPacketConns, upgradeReadyFile, err := inheritListeners(s.config)
+	if err != nil {
+		return err
+	}
+
+	var http3Conn net.PacketConn
+
+	if s.http3 != nil {
+		http3Conn, inheritedPacketConns = takeNamedPacketConn(inheritedPacketConns, http3ListenerName)
+	}
+
+	closeNamedPacketConns(inheritedPacketConns)
+
+	var promListener net.Listener
+
+	if s.promServer != nil {
+		promListener, inherited = takeNamedListener(inherited, prometheusListenerName)
+	}
+
+	namedListeners := make([]namedListener, len(s.options.listeners))
+
+	for i, listener := range s.options.listeners {
+		namedListeners[i] = namedListener{listener: listener}
+	}
+
+	if len(namedListeners) == 0 {
+		namedListeners, err = openListeners(
+			ctx,
+			&listenConfig,
+			s.config.GetListenAddresses(),
+			inherited,
+			listenerConfig.GetAcceptLoops(),
+		)
+	} else {
+		closeNamedListeners(inherited)
+	}
+
+	if err == nil && listenerConfig.Backlog > 0 {
+		for _, nl := range namedListeners {
+			err = setListenBacklog(nl.listener, listenerConfig.Backlog)
+			if err != nil {
+				break
+			}
+		}
+	}
+
+	if err == nil && s.promServer != nil && promListener == nil {
+		promListener, err = listenConfig.Listen(ctx, "tcp", s.promServer.Addr)
+	}
+
+	if err == nil && s.http3 != nil {
+		err = s.http3.listen(ctx, &listenConfig, s.config.GetPort(), http3Conn)
+	}
+
+	// Gauges are observed once the server starts, so servers that are never started do not leak callbacks.
+	if err == nil {
+		err = s.observeMetrics()
+	}
+
+	if err != nil {
+		closeNamedListeners(namedListeners)
+
+		if http3Conn != nil {
+			_ = http3Conn.Close()
+		}
+
+		if promListener != nil {
+			_ = promListener.Close()
+		}
+
+		if upgradeReadyFile != nil {
+			_ = upgradeReadyFile.Close()
+		}
+
+		return err
+	}
+
+	// Requests must not be cancelled by the quit signal so they can be drained gracefully.
+	baseContext := context.WithoutCancel(ctx)
+
+	s.httpServer.BaseContext = func(_ net.Listener) context.Context {
+		return baseContext
+	}
+	s.listeners = namedListeners
+	s.promListener = promListener
+	s.started = true
+
+	backgroundCtx, cancelBackground := context.WithCancel(baseContext)
+	s.cancelBackground = cancelBackground
+
+	if s.certReloader != nil {
+		go s.certReloader.Run(backgroundCtx)
+	}
+
+	if s.expiryMonitor != nil {
+		go s.expiryMonitor.Run(backgroundCtx, defaultTLSExpiryCheckInterval)
+	}
+
+	if s.sessionTicketKeys != nil {
+		go s.sessionTicketKeys.Run(backgroundCtx)
+	}
+
+	if s.connectionLimiter != nil {
+		go s.connectionLimiter.Run(backgroundCtx)
+	}
+
+	go s.tracker.Run(backgroundCtx)
+
+	for _, nl := range namedListeners {
+		go s.serve(s.wrapListener(nl.listener))
+	}
+
+	if promListener != nil {
+		go s.servePrometheus(promListener)
+	}
+
+	if s.http3 != nil {
+		go s.serveHTTP3()
+	}
+
+	s.healthRegistry.setStarted(true)
+
+	if upgradeReadyFile != nil {
+		notifyUpgradeReady(upgradeReadyFile)
+	}
+
+	return nil
+}
+
+// Shutdown gracefully shuts down the server. The health check fails during the pre-stop delay
+// while the server keeps serving traffic, then in-flight requests are drained.
+// Connections are forcibly closed once the context is done.
+// If the context has no deadline, the shutdown timeout of the config is used.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	started := s.started
+	s.mu.Unlock()
+
+	if !started {
+		s.stop(nil)
+
+		return nil
+	}
+
+	s.healthRegistry.setDraining(true)
+
+	preStopDelay := time.Duration(s.config.PreStopDelay)
+	if preStopDelay > 0 {
+		slog.Info(
+			"waiting for the pre-stop delay before shutting down",
+			slog.Duration("delay", preStopDelay),
+		)
+
+		timer := time.NewTimer(preStopDelay)
+
+		select {
+		case <-s.done:
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+
+		timer.Stop()
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.config.GetShutdownTimeout())
+		defer cancel()
+	}
+
+	var http3Done chan error
+
+	// HTTP/3 connections are drained concurrently with TCP connections within the same deadline.
+	if s.http3 != nil {
+		http3Done = make(chan error, 1)
+
+		go func() {
+			http3Done <- s.http3.shutdown(ctx)
+		}()
+	}
+
+	err := shutdownServer(ctx, s.httpServer, s.tracker)
+
+	if http3Done != nil {
+		http3Err := <-http3Done
+		if http3Err != nil {
+			slog.Warn("failed to shutdown HTTP/3 server: " + http3Err.Error())
+		}
+	}
+
+	if s.promServer != nil {
+		promErr := shutdownServer(ctx, s.promServer, s.promTracker)
+		if promErr != nil {
+			slog.Warn("failed to shutdown prometheus server: " + promErr.Error())
+		}
+	}
+
+	s.stop(nil)
+
+	return err
+}
+
+// Addr returns the address of the first listener. Returns nil if the server is not started.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.listeners) == 0 {
+		return nil
+	}
+
+	return s.listeners[0].listener.Addr()
+}
+
+// Addrs returns addresses of all listeners.
+func (s *Server) Addrs() []net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]net.Addr, len(s.listeners))
+
+	for i, nl := range s.listeners {
+		results[i] = nl.listener.Addr()
+	}
+
+	return results
+}
+
+// HTTP3Addr returns the UDP address of the HTTP/3 server.
+// Returns nil if HTTP/3 is disabled or the server is not started.
+func (s *Server) HTTP3Addr() net.Addr {
+	if s.http3 == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.http3.addr()
+}
+
+// Connections returns open connections of the HTTP server over TCP in the order of creation.
+func (s *Server) Connections() []ConnectionInfo {
+	return s.tracker.Connections()
+}
+
+// Done returns a channel that is closed when the server stops.
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns the error that caused the server to stop.
+// Returns nil if the server is running or was stopped by Shutdown.
+func (s *Server) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+func (s *Server) serve(listener net.Listener) {
+	var err error
+
+	if s.tlsConfig != nil {
+		slog.Info("Listening server and serving TLS on " + listener.Addr().String())
+
+		// Unlike ServeTLS, the TLS config is not cloned,
+		// so session ticket keys that are rotated later apply to new connections.
+		err = s.httpServer.Serve(tls.NewListener(listener, s.tlsConfig))
+	} else {
+		slog.Info("Listening server on " + listener.Addr().String())
+
+		err = s.httpServer.Serve(listener)
+	}
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.fail(err)
+	}
+}
+
+// wrapListener wraps the listener to serve connections. The original listener is kept to be handed off during upgrades.
+func (s *Server) wrapListener(listener net.Listener) net.Listener {
+	if s.config.Listener != nil && !s.config.Listener.IsNoDelay() {
+		listener = noDelayListener{Listener: listener}
+	}
+
+	// Limits apply to the peer address, so the PROXY protocol header is not read before the connection is admitted.
+	if s.connectionLimiter != nil {
+		listener = s.connectionLimiter.Listen(listener)
+	}
+
+	if s.config.ProxyProtocol == nil {
+		return listener
+	}
+
+	// The config is validated when the server is created.
+	ppl, _ := listeners.NewProxyProtocolListener(listener, *s.config.ProxyProtocol)
+
+	return ppl
+}
+
+func (s *Server) serveHTTP3() {
+	err := s.http3.serve()
+	if err != nil {
+		// TCP listeners keep serving requests. The health check reports the degraded HTTP/3 server.
+		slog.Error("HTTP/3 server stopped", slog.String("error", err.Error()))
+	}
+}
+
+func (s *Server) servePrometheus(listener net.Listener) {
+	err := s.promServer.Serve(listener)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.fail(err)
+	}
+}
+
+// fail closes all servers immediately and stops with the error.
+func (s *Server) fail(err error) {
+	_ = s.httpServer.Close()
+
+	if s.promServer != nil {
+		_ = s.promServer.Close()
+	}
+
+	if s.http3 != nil {
+		s.http3.close()
+	}
+
+	s.stop(err)
+}
+
+func (s *Server) stop(err error) {
+	s.doneOnce.Do(func() {
+		s.mu.Lock()
+		if s.cancelBackground != nil {
+			s.cancelBackground()
+		}
+		s.mu.Unlock()
+
+		s.err = err
+		close(s.done)
+	})
+}
+
+func registerHealthRoutes(router *chi.Mux, healthRegistry *HealthRegistry) {
+	router.Get(pathHealthz, func(w http.ResponseWriter, _ *http.Request) {
+		body := "OK"
+
+		// The health check fails while the server keeps serving traffic during the pre-stop delay.
+		if !healthRegistry.Ready() {
+			body = http.StatusText(http.StatusServiceUnavailable)
+
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		_, err := w.Write([]byte(body))
+		if err != nil {
+			slog.Error("failed to write response: " + err.Error())
+		}
+	})
+	router.Get(pathLivez, healthRegistry.Handler(HealthProbeLiveness))
+	router.Get(pathReadyz, healthRegistry.Handler(HealthProbeReadiness))
+	router.Get(pathStartupz, healthRegistry.Handler(HealthProbeStartup))
+}
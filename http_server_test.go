@@ -0,0 +1,128 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServer(t *testing.T) {
+	t.Run("nil config returns error", func(t *testing.T) {
+		_, err := NewServer(nil, NewRouter(nil, slog.Default()))
+		if !errors.Is(err, errServerConfigRequired) {
+			t.Errorf("expected errServerConfigRequired, got %v", err)
+		}
+	})
+
+	t.Run("start and shutdown", func(t *testing.T) {
+		server := newTestServer(t, &ServerConfig{})
+
+		if server.Addr() != nil {
+			t.Error("expected nil address before the server starts")
+		}
+
+		if err := server.Start(context.Background()); err != nil {
+			t.Fatalf("failed to start server: %v", err)
+		}
+
+		if err := server.Start(context.Background()); !errors.Is(err, errServerAlreadyStarted) {
+			t.Errorf("expected errServerAlreadyStarted, got %v", err)
+		}
+
+		baseURL := "http://" + server.Addr().String()
+		waitForStatus(t, baseURL+"/test", http.StatusOK)
+		waitForStatus(t, baseURL+"/startupz", http.StatusOK)
+
+		select {
+		case <-server.Done():
+			t.Fatal("expected the server to be running")
+		default:
+		}
+
+		if err := server.Shutdown(context.Background()); err != nil {
+			t.Fatalf("failed to shutdown server: %v", err)
+		}
+
+		select {
+		case <-server.Done():
+		case <-time.After(time.Second):
+			t.Fatal("expected the server to be stopped")
+		}
+
+		if err := server.Err(); err != nil {
+			t.Errorf("expected nil error, got %v", err)
+		}
+	})
+
+	t.Run("stops when serving fails", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+
+		server := newTestServer(t, &ServerConfig{}, WithListener(listener))
+
+		if err := server.Start(context.Background()); err != nil {
+			t.Fatalf("failed to start server: %v", err)
+		}
+
+		_ = listener.Close()
+
+		select {
+		case <-server.Done():
+		case <-time.After(time.Second):
+			t.Fatal("expected the server to be stopped")
+		}
+
+		if server.Err() == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func newTestServer(t *testing.T, config *ServerConfig, options ...ServerOption) *Server {
+	t.Helper()
+
+	router := NewRouter(config, slog.Default())
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	if len(options) == 0 {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+
+		options = append(options, WithListener(listener))
+	}
+
+	server, err := NewServer(config, router, options...)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = server.Shutdown(context.Background())
+	})
+
+	return server
+}
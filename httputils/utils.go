@@ -16,6 +16,7 @@
 package httputils
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -198,13 +199,51 @@ func GetURLParamInt64(r *http.Request, param string) (int64, error) {
 	return value, nil
 }
 
-// GetRequestLogger gets the logger from request with request_id.
+// GetRequestLogger gets the logger that is stored in the request context.
+// Fall back to the default logger with request_id if the context has no logger.
 func GetRequestLogger(r *http.Request) *slog.Logger {
-	return slog.Default().With(slog.String("request_id", getRequestID(r)))
+	ctx := r.Context()
+
+	if RequestIDFromContext(ctx) == "" {
+		ctx = WithRequestID(ctx, GetRequestID(r))
+	}
+
+	return LoggerFromContext(ctx)
+}
+
+type loggerContextKey struct{}
+
+// WithLogger returns a copy of the context that stores the logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
 }
 
-func getRequestID(r *http.Request) string {
-	requestID := r.Header.Get("x-request-id")
+// LoggerFromContext gets the logger that is stored in the context.
+// Fall back to the default logger with the request ID of the context if the context has no logger.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger)
+	if ok {
+		return logger
+	}
+
+	logger = slog.Default()
+
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		logger = logger.With(slog.String("request_id", requestID))
+	}
+
+	return logger
+}
+
+// GetRequestID gets the request ID that the RequestID middleware stores in the request context.
+// Fall back to the x-request-id header or the trace ID of the request.
+func GetRequestID(r *http.Request) string {
+	requestID := RequestIDFromContext(r.Context())
+	if requestID != "" {
+		return requestID
+	}
+
+	requestID = r.Header.Get("x-request-id")
 	if requestID != "" {
 		return requestID
 	}
@@ -216,3 +255,17 @@ func getRequestID(r *http.Request) string {
 
 	return uuid.NewString()
 }
+
+type requestIDContextKey struct{}
+
+// WithRequestID returns a copy of the context that stores the request ID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext gets the request ID that is stored in the context. Returns an empty string if not exist.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+
+	return requestID
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -257,6 +258,51 @@ func TestGetRequestLogger(t *testing.T) {
 	})
 }
 
+func TestLoggerFromContext(t *testing.T) {
+	if LoggerFromContext(context.Background()) != slog.Default() {
+		t.Error("expected the default logger")
+	}
+
+	logger := slog.New(slog.DiscardHandler)
+	req := httptest.NewRequest("GET", "/test", nil)
+	req = req.WithContext(WithLogger(req.Context(), logger))
+
+	if LoggerFromContext(req.Context()) != logger {
+		t.Error("expected the logger in the context")
+	}
+
+	if GetRequestLogger(req) != logger {
+		t.Error("expected the request logger to be the logger in the context")
+	}
+}
+
+func TestGetRequestID(t *testing.T) {
+	t.Run("stored in the context", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("x-request-id", "header-request-id")
+		req = req.WithContext(WithRequestID(req.Context(), "context-request-id"))
+
+		if requestID := GetRequestID(req); requestID != "context-request-id" {
+			t.Errorf("expected context-request-id, got %s", requestID)
+		}
+	})
+
+	t.Run("with x-request-id header", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("x-request-id", "header-request-id")
+
+		if requestID := GetRequestID(req); requestID != "header-request-id" {
+			t.Errorf("expected header-request-id, got %s", requestID)
+		}
+	})
+
+	t.Run("not stored", func(t *testing.T) {
+		if requestID := RequestIDFromContext(context.Background()); requestID != "" {
+			t.Errorf("expected empty request ID, got %s", requestID)
+		}
+	})
+}
+
 func TestSetWriteResponseErrorAttribute(t *testing.T) {
 	t.Run("set error attribute", func(t *testing.T) {
 		_, span := noop.NewTracerProvider().Tracer("test").Start(context.Background(), "test")
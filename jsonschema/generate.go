@@ -16,11 +16,13 @@ package main
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/invopop/jsonschema"
 	"github.com/relychan/gohttps"
@@ -54,7 +56,7 @@ func jsonSchemaConfiguration() error {
 		Type:        "string",
 		Description: "Duration string",
 		MinLength:   new(uint64(2)),
-		Pattern:     `^(\d+(\.\d+)?h)?(\d+(\.\d+)?m)?(\d+(\.\d+)?s)?(\d+(\.\d+)?ms)?$`,
+		Pattern:     `^-?(\d+(\.\d+)?h)?(\d+(\.\d+)?m)?(\d+(\.\d+)?s)?(\d+(\.\d+)?ms)?$`,
 	}
 
 	reflectSchema.Definitions["ServerConfig"].Properties.Set("requestTimeout", &jsonschema.Schema{
@@ -83,6 +85,78 @@ If zero, the value of ReadTimeout is used. If negative, or if zero and ReadTimeo
 		Ref:         "#/$defs/Duration",
 	})
 
+	reflectSchema.Definitions["ServerConfig"].Properties.Set("shutdownTimeout", &jsonschema.Schema{
+		Description: "The maximum duration to wait for in-flight requests to complete when the server is shutting down.\nConnections that are still open once the deadline passes are forcibly closed.\nIf zero or negative, the default value is 30 seconds.",
+		Ref:         "#/$defs/Duration",
+	})
+	reflectSchema.Definitions["ServerConfig"].Properties.Set("preStopDelay", &jsonschema.Schema{
+		Description: "The duration to keep serving traffic after receiving the quit signal before the graceful shutdown starts.\nDuring this period the health check endpoint responds with 503 Service Unavailable,\nso load balancers and Kubernetes stop routing new requests to the server.",
+		Ref:         "#/$defs/Duration",
+	})
+	reflectSchema.Definitions["ServerConfig"].Properties.Set("upgradeTimeout", &jsonschema.Schema{
+		Description: "The maximum duration to wait for the new process to be ready during the upgrade. Default is 1 minute.",
+		Ref:         "#/$defs/Duration",
+	})
+	reflectSchema.Definitions["ListenerConfig"].Properties.Set("keepAliveIdle", &jsonschema.Schema{
+		Description: "The idle duration of a connection before the first keep-alive probe is sent. Default is 15 seconds.",
+		Ref:         "#/$defs/Duration",
+	})
+	reflectSchema.Definitions["ListenerConfig"].Properties.Set("keepAliveInterval", &jsonschema.Schema{
+		Description: "The duration between keep-alive probes. Default is 15 seconds.",
+		Ref:         "#/$defs/Duration",
+	})
+	reflectSchema.Definitions["ProxyProtocolConfig"].Properties.Set("headerTimeout", &jsonschema.Schema{
+		Description: "The maximum duration to read the PROXY protocol header. Default is 10 seconds.",
+		Ref:         "#/$defs/Duration",
+	})
+	reflectSchema.Definitions["ConnectionLimitConfig"].Properties.Set("queueTimeout", &jsonschema.Schema{
+		Description: "The maximum duration that a connection waits in the queue before it is rejected. Default is 10 seconds.",
+		Ref:         "#/$defs/Duration",
+	})
+	reflectSchema.Definitions["MinDataRateConfig"].Properties.Set("gracePeriod", &jsonschema.Schema{
+		Description: "The duration that a transfer may stay below the minimum rate when it starts. Default is 5 seconds.",
+		Ref:         "#/$defs/Duration",
+	})
+	reflectSchema.Definitions["AccessLogConfig"].Properties.Set("slowRequestThreshold", &jsonschema.Schema{
+		Description: "Requests that take longer than the threshold are always logged at the WARN level. Disabled if zero.",
+		Ref:         "#/$defs/Duration",
+	})
+
+	reflectSchema.Definitions["TLSConfig"].Properties.Set("reloadInterval", &jsonschema.Schema{
+		Description: "The interval to poll the certificate and key files for changes. The new key pair is served without restarting.\nDefault is 1 minute. Set a negative value to disable.",
+		Ref:         "#/$defs/Duration",
+	})
+
+	reflectSchema.Definitions["TLSConfig"].Properties.Set("expiryWarningThresholds", &jsonschema.Schema{
+		Description: "The durations before the expiry of a served certificate to log a warning at. Each threshold is logged once per certificate.\nDefault is 30 days, 7 days and 1 day. Set a negative value to disable warnings.",
+		Type:        "array",
+		Items:       &jsonschema.Schema{Ref: "#/$defs/Duration"},
+	})
+
+	reflectSchema.Definitions["TLSConfig"].Properties.Set("sessionTicketKeyRotationInterval", &jsonschema.Schema{
+		Description: "The interval to rotate the session ticket key that encrypts new tickets. With the key file, the key advances\nthrough the keys of the file at the same time on every replica. Otherwise, keys are generated in memory.\nIf both are empty, Go generates and rotates keys automatically.",
+		Ref:         "#/$defs/Duration",
+	})
+
+	reflectSchema.Definitions["HTTP2Config"].Properties.Set("sendPingTimeout", &jsonschema.Schema{
+		Description: "The duration after which a health check using a ping frame is carried out if no frame is received on a connection.\nIf zero, no health check is performed.",
+		Ref:         "#/$defs/Duration",
+	})
+	reflectSchema.Definitions["HTTP2Config"].Properties.Set("pingTimeout", &jsonschema.Schema{
+		Description: "The duration after which a connection is closed if a response to a ping is not received. Default is 15 seconds.",
+		Ref:         "#/$defs/Duration",
+	})
+	reflectSchema.Definitions["HTTP2Config"].Properties.Set("writeByteTimeout", &jsonschema.Schema{
+		Description: "The duration after which a connection is closed if no data can be written to it.\nThe timeout begins when data is available to write, and is extended whenever any bytes are written.",
+		Ref:         "#/$defs/Duration",
+	})
+	reflectSchema.Definitions["HTTP3Config"].Properties.Set("maxIdleTimeout", &jsonschema.Schema{
+		Description: "The duration after which an idle QUIC connection is closed. Default is 30 seconds.",
+		Ref:         "#/$defs/Duration",
+	})
+
+	setTLSCipherSuitesEnum(reflectSchema.Definitions["TLSConfig"])
+
 	buffer := new(bytes.Buffer)
 	enc := json.NewEncoder(buffer)
 	enc.SetEscapeHTML(false)
@@ -98,3 +172,19 @@ If zero, the value of ReadTimeout is used. If negative, or if zero and ReadTimeo
 		buffer.Bytes(), 0o644,
 	)
 }
+
+// setTLSCipherSuitesEnum sets the enum of the cipher suites property to secure TLS 1.0-1.2 cipher suites of Go.
+func setTLSCipherSuitesEnum(tlsConfigSchema *jsonschema.Schema) {
+	cipherSuitesSchema, ok := tlsConfigSchema.Properties.Get("cipherSuites")
+	if !ok || cipherSuitesSchema.Items == nil {
+		return
+	}
+
+	for _, cipherSuite := range tls.CipherSuites() {
+		if slices.ContainsFunc(cipherSuite.SupportedVersions, func(version uint16) bool {
+			return version < tls.VersionTLS13
+		}) {
+			cipherSuitesSchema.Items.Enum = append(cipherSuitesSchema.Items.Enum, cipherSuite.Name)
+		}
+	}
+}
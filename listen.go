@@ -0,0 +1,415 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"os"
+	"os/user"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const unixSocketScheme = "unix://"
+
+var (
+	errListenAddressRequired      = errors.New("listen address is required")
+	errListenAddressInvalid       = errors.New("invalid listen address")
+	errUnixSocketPathRequired     = errors.New("unix socket path is required")
+	errUnixSocketOptionsNotUnix   = errors.New("socket options are only supported by unix socket addresses")
+	errUnixSocketModeInvalid      = errors.New("invalid unix socket file mode")
+	errUnixSocketAddressInUse     = errors.New("unix socket address is already in use")
+	errUnixSocketOwnerInvalid     = errors.New("invalid unix socket owner")
+	errUnixSocketGroupInvalid     = errors.New("invalid unix socket group")
+	errUnixSocketPathNotAvailable = errors.New("unix socket path exists and is not a socket")
+)
+
+// ListenAddress represents the configuration of an address that the server listens to.
+type ListenAddress struct {
+	// The address to listen to. Accept host:port, [::1]:port or unix:///path/to/file.sock formats.
+	// Listen to all interfaces if the host is empty, e.g. :8080.
+	Address string `json:"address" yaml:"address"`
+	// The name of the listener to be matched with file descriptors inherited by socket activation (LISTEN_FDNAMES).
+	// The address is listened to if there is no inherited file descriptor with the same name.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// The file mode of the Unix domain socket file in octal notation, e.g. 0660.
+	SocketMode string `json:"socketMode,omitempty" yaml:"socketMode,omitempty" jsonschema:"pattern=^0?[0-7]{3}$"`
+	// The user name or ID that owns the Unix domain socket file.
+	SocketOwner string `json:"socketOwner,omitempty" yaml:"socketOwner,omitempty"`
+	// The group name or ID that owns the Unix domain socket file.
+	SocketGroup string `json:"socketGroup,omitempty" yaml:"socketGroup,omitempty"`
+}
+
+// Validate checks if the configuration is valid.
+func (la ListenAddress) Validate() error {
+	network, address, err := la.Parse()
+	if err != nil {
+		return err
+	}
+
+	if network != "unix" {
+		if la.SocketMode != "" || la.SocketOwner != "" || la.SocketGroup != "" {
+			return fmt.Errorf("%w: %s", errUnixSocketOptionsNotUnix, la.Address)
+		}
+
+		return nil
+	}
+
+	if address == "" {
+		return errUnixSocketPathRequired
+	}
+
+	_, err = la.parseSocketMode()
+
+	return err
+}
+
+// Parse parses the network and address to be listened to.
+func (la ListenAddress) Parse() (string, string, error) {
+	if la.Address == "" {
+		return "", "", errListenAddressRequired
+	}
+
+	if path, ok := strings.CutPrefix(la.Address, unixSocketScheme); ok {
+		return "unix", path, nil
+	}
+
+	_, port, err := net.SplitHostPort(la.Address)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %w", errListenAddressInvalid, err)
+	}
+
+	portNumber, err := strconv.Atoi(port)
+	if err != nil || portNumber < 0 || portNumber > 65535 {
+		return "", "", fmt.Errorf("%w: invalid port %s", errListenAddressInvalid, port)
+	}
+
+	return "tcp", la.Address, nil
+}
+
+func (la ListenAddress) parseSocketMode() (fs.FileMode, error) {
+	if la.SocketMode == "" {
+		return 0, nil
+	}
+
+	mode, err := strconv.ParseUint(la.SocketMode, 8, 32)
+	if err != nil || mode > uint64(fs.ModePerm) {
+		return 0, fmt.Errorf("%w: %s", errUnixSocketModeInvalid, la.SocketMode)
+	}
+
+	return fs.FileMode(mode), nil
+}
+
+// namedListener represents a listener with the name to be matched when it is handed off to another process.
+type namedListener struct {
+	name     string
+	listener net.Listener
+}
+
+// openListeners creates listeners from the configured addresses.
+// Inherited listeners take precedence over the addresses.
+// Every TCP address is listened to by the number of accept loops, that requires SO_REUSEPORT if greater than 1.
+func openListeners(
+	ctx context.Context,
+	listenConfig *net.ListenConfig,
+	addresses []ListenAddress,
+	inherited []namedListener,
+	acceptLoops int,
+) ([]namedListener, error) {
+	// Serve all inherited listeners if addresses are not matched by names.
+	if len(inherited) > 0 && !slices.ContainsFunc(addresses, func(la ListenAddress) bool {
+		return la.Name != ""
+	}) {
+		for _, nl := range inherited {
+			slog.Info("Inherited the listener " + nl.name + " on " + nl.listener.Addr().String())
+		}
+
+		return inherited, nil
+	}
+
+	results := make([]namedListener, 0, len(addresses))
+
+	for _, address := range addresses {
+		if address.Name != "" {
+			var matched bool
+
+			// Take all inherited listeners of the name, e.g. of several accept loops.
+			for {
+				var listener net.Listener
+
+				listener, inherited = takeNamedListener(inherited, address.Name)
+				if listener == nil {
+					break
+				}
+
+				slog.Info("Inherited the listener " + address.Name + " on " + listener.Addr().String())
+
+				results = append(results, namedListener{
+					name:     address.Name,
+					listener: listener,
+				})
+				matched = true
+			}
+
+			if matched {
+				continue
+			}
+		}
+
+		listener, err := listen(ctx, listenConfig, address)
+		if err != nil {
+			closeNamedListeners(results)
+			closeNamedListeners(inherited)
+
+			return nil, err
+		}
+
+		results = append(results, namedListener{
+			name:     address.Name,
+			listener: listener,
+		})
+
+		if _, ok := listener.Addr().(*net.TCPAddr); !ok {
+			continue
+		}
+
+		// Other accept loops listen to the bound address in case the port is chosen by the system.
+		for range acceptLoops - 1 {
+			listener, err := listenConfig.Listen(ctx, "tcp", listener.Addr().String())
+			if err != nil {
+				closeNamedListeners(results)
+				closeNamedListeners(inherited)
+
+				return nil, err
+			}
+
+			results = append(results, namedListener{
+				name:     address.Name,
+				listener: listener,
+			})
+		}
+	}
+
+	for _, nl := range inherited {
+		slog.Warn("closing the inherited listener " + nl.name + " that does not match any listen address")
+
+		_ = nl.listener.Close()
+	}
+
+	return results, nil
+}
+
+// takeNamedListener removes the listener with the name from the list and returns it.
+func takeNamedListener(listeners []namedListener, name string) (net.Listener, []namedListener) {
+	index := slices.IndexFunc(listeners, func(nl namedListener) bool {
+		return nl.name == name
+	})
+
+	if index < 0 {
+		return nil, listeners
+	}
+
+	listener := listeners[index].listener
+
+	return listener, slices.Delete(listeners, index, index+1)
+}
+
+func closeNamedListeners(listeners []namedListener) {
+	for _, nl := range listeners {
+		_ = nl.listener.Close()
+	}
+}
+
+// namedPacketConn represents a packet-oriented socket, e.g. the UDP socket of HTTP/3,
+// with the name to be matched when it is handed off to another process.
+type namedPacketConn struct {
+	name string
+	conn net.PacketConn
+}
+
+// takeNamedPacketConn removes the packet conn with the name from the list and returns it.
+func takeNamedPacketConn(packetConns []namedPacketConn, name string) (net.PacketConn, []namedPacketConn) {
+	index := slices.IndexFunc(packetConns, func(npc namedPacketConn) bool {
+		return npc.name == name
+	})
+
+	if index < 0 {
+		return nil, packetConns
+	}
+
+	conn := packetConns[index].conn
+
+	return conn, slices.Delete(packetConns, index, index+1)
+}
+
+func closeNamedPacketConns(packetConns []namedPacketConn) {
+	for _, npc := range packetConns {
+		_ = npc.conn.Close()
+	}
+}
+
+// listen creates a listener from the address config.
+func listen(ctx context.Context, listenConfig *net.ListenConfig, la ListenAddress) (net.Listener, error) {
+	network, address, err := la.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	if network != "unix" {
+		return listenConfig.Listen(ctx, network, address)
+	}
+
+	err = removeStaleUnixSocket(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	var listener net.Listener
+
+	listenUnix := func() error {
+		listener, err = listenConfig.Listen(ctx, network, address)
+
+		return err
+	}
+
+	// The socket file is created accessible by the owner only, so other local users cannot connect
+	// before the configured mode and owner are applied.
+	if la.SocketMode != "" || la.SocketOwner != "" || la.SocketGroup != "" {
+		err = withUmask(0o177, listenUnix)
+	} else {
+		err = listenUnix()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = setupUnixSocketFile(address, la)
+	if err != nil {
+		_ = listener.Close()
+
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+// removeStaleUnixSocket removes the socket file left by a previous process that was not shut down properly.
+func removeStaleUnixSocket(ctx context.Context, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	if info.Mode().Type() != fs.ModeSocket {
+		return fmt.Errorf("%w: %s", errUnixSocketPathNotAvailable, path)
+	}
+
+	dialer := net.Dialer{
+		Timeout: time.Second,
+	}
+
+	conn, err := dialer.DialContext(ctx, "unix", path)
+	if err == nil {
+		_ = conn.Close()
+
+		return fmt.Errorf("%w: %s", errUnixSocketAddressInUse, path)
+	}
+
+	return os.Remove(path)
+}
+
+func setupUnixSocketFile(path string, la ListenAddress) error {
+	mode, err := la.parseSocketMode()
+	if err != nil {
+		return err
+	}
+
+	if mode != 0 {
+		err := os.Chmod(path, mode)
+		if err != nil {
+			return err
+		}
+	}
+
+	if la.SocketOwner == "" && la.SocketGroup == "" {
+		return nil
+	}
+
+	uid, gid := -1, -1
+
+	if la.SocketOwner != "" {
+		uid, err = lookupUserID(la.SocketOwner)
+		if err != nil {
+			return err
+		}
+	}
+
+	if la.SocketGroup != "" {
+		gid, err = lookupGroupID(la.SocketGroup)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.Chown(path, uid, gid)
+}
+
+func lookupUserID(name string) (int, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+
+	u, err := user.Lookup(name)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", errUnixSocketOwnerInvalid, err)
+	}
+
+	id, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", errUnixSocketOwnerInvalid, err)
+	}
+
+	return id, nil
+}
+
+func lookupGroupID(name string) (int, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", errUnixSocketGroupInvalid, err)
+	}
+
+	id, err := strconv.Atoi(g.Gid)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", errUnixSocketGroupInvalid, err)
+	}
+
+	return id, nil
+}
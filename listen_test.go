@@ -0,0 +1,203 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestListenAddress_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		address ListenAddress
+		wantErr error
+	}{
+		{
+			name:    "empty address",
+			address: ListenAddress{},
+			wantErr: errListenAddressRequired,
+		},
+		{
+			name:    "all interfaces",
+			address: ListenAddress{Address: ":8080"},
+		},
+		{
+			name:    "localhost",
+			address: ListenAddress{Address: "127.0.0.1:8080"},
+		},
+		{
+			name:    "ipv6",
+			address: ListenAddress{Address: "[::1]:8080"},
+		},
+		{
+			name:    "missing port",
+			address: ListenAddress{Address: "localhost"},
+			wantErr: errListenAddressInvalid,
+		},
+		{
+			name:    "invalid port",
+			address: ListenAddress{Address: "localhost:http"},
+			wantErr: errListenAddressInvalid,
+		},
+		{
+			name:    "socket options on tcp address",
+			address: ListenAddress{Address: ":8080", SocketMode: "0660"},
+			wantErr: errUnixSocketOptionsNotUnix,
+		},
+		{
+			name:    "unix socket",
+			address: ListenAddress{Address: "unix:///run/app.sock", SocketMode: "0660", SocketOwner: "www-data"},
+		},
+		{
+			name:    "empty unix socket path",
+			address: ListenAddress{Address: "unix://"},
+			wantErr: errUnixSocketPathRequired,
+		},
+		{
+			name:    "invalid unix socket mode",
+			address: ListenAddress{Address: "unix:///run/app.sock", SocketMode: "0999"},
+			wantErr: errUnixSocketModeInvalid,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.address.Validate()
+			if tc.wantErr == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if !errors.Is(err, tc.wantErr) {
+				t.Errorf("expected %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestServerMultipleListeners(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "server.sock")
+
+	// a stale socket file left by a previous process must be removed.
+	staleListener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	staleListener.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = staleListener.Close()
+
+	config := &ServerConfig{
+		Addresses: []ListenAddress{
+			{Address: "127.0.0.1:0"},
+			{Address: "unix://" + socketPath, SocketMode: "0600", SocketOwner: strconv.Itoa(os.Getuid())},
+		},
+	}
+
+	router := NewRouter(config, slog.Default())
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server, err := NewServer(config, router)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+
+	addrs := server.Addrs()
+	if len(addrs) != 2 {
+		t.Fatalf("expected 2 addresses, got %d", len(addrs))
+	}
+
+	waitForStatus(t, "http://"+server.Addr().String()+"/test", http.StatusOK)
+
+	info, err := os.Stat(socketPath)
+	if err != nil {
+		t.Fatalf("failed to stat socket file: %v", err)
+	}
+
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected socket file mode 0600, got %o", info.Mode().Perm())
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+
+	resp, err := client.Get("http://unix/test")
+	if err != nil {
+		t.Fatalf("failed to request via unix socket: %v", err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	client.CloseIdleConnections()
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("failed to shutdown server: %v", err)
+	}
+
+	if _, err := os.Stat(socketPath); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected the socket file to be removed, got %v", err)
+	}
+}
+
+func TestListenUnixSocketInUse(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "server.sock")
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			_ = conn.Close()
+		}
+	}()
+
+	var listenConfig net.ListenConfig
+
+	_, err = listen(context.Background(), &listenConfig, ListenAddress{Address: "unix://" + socketPath})
+	if !errors.Is(err, errUnixSocketAddressInUse) {
+		t.Errorf("expected errUnixSocketAddressInUse, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/relychan/goutils"
+)
+
+var (
+	errListenerConfigInvalid       = errors.New("invalid listener config")
+	errSocketOptionsNotSupported   = errors.New("socket option is not supported on this platform")
+	errAcceptLoopsRequireReusePort = errors.New("multiple accept loops require reusePort")
+)
+
+// ListenerConfig represents socket options of TCP listeners and accepted connections.
+type ListenerConfig struct {
+	// Disable TCP keep-alive probes on accepted connections.
+	DisableKeepAlive bool `env:"SERVER_LISTENER_DISABLE_KEEP_ALIVE" json:"disableKeepAlive,omitempty" yaml:"disableKeepAlive,omitempty"`
+	// The idle duration of a connection before the first keep-alive probe is sent. Default is 15 seconds.
+	KeepAliveIdle goutils.Duration `env:"SERVER_LISTENER_KEEP_ALIVE_IDLE" json:"keepAliveIdle,omitempty" yaml:"keepAliveIdle,omitempty"`
+	// The duration between keep-alive probes. Default is 15 seconds.
+	KeepAliveInterval goutils.Duration `env:"SERVER_LISTENER_KEEP_ALIVE_INTERVAL" json:"keepAliveInterval,omitempty" yaml:"keepAliveInterval,omitempty"`
+	// The number of unacknowledged keep-alive probes before the connection is dropped. Default is 9.
+	KeepAliveCount int `env:"SERVER_LISTENER_KEEP_ALIVE_COUNT" json:"keepAliveCount,omitempty" yaml:"keepAliveCount,omitempty" jsonschema:"minimum=0"`
+	// Set TCP_NODELAY on accepted connections to send small writes without delay (Nagle's algorithm is disabled).
+	// Default is true.
+	NoDelay *bool `env:"SERVER_LISTENER_NO_DELAY" json:"noDelay,omitempty" yaml:"noDelay,omitempty"`
+	// Set SO_REUSEPORT on listeners so several sockets, e.g. of other processes, can listen to the same address.
+	// Only supported on Linux.
+	ReusePort bool `env:"SERVER_LISTENER_REUSE_PORT" json:"reusePort,omitempty" yaml:"reusePort,omitempty"`
+	// The number of listeners that are opened for every TCP address with SO_REUSEPORT.
+	// The kernel distributes connections across their accept loops. Require reusePort. Default is 1.
+	AcceptLoops int `env:"SERVER_LISTENER_ACCEPT_LOOPS" json:"acceptLoops,omitempty" yaml:"acceptLoops,omitempty" jsonschema:"minimum=0"`
+	// The maximum length of the queue of pending connections of TCP listeners.
+	// Default is the system limit, e.g. net.core.somaxconn. Only supported on Linux.
+	Backlog int `env:"SERVER_LISTENER_BACKLOG" json:"backlog,omitempty" yaml:"backlog,omitempty" jsonschema:"minimum=0"`
+}
+
+// Validate checks if the configuration is valid.
+func (lc ListenerConfig) Validate() error {
+	switch {
+	case lc.KeepAliveIdle < 0 || lc.KeepAliveInterval < 0 || lc.KeepAliveCount < 0:
+		return fmt.Errorf("%w: keep-alive options must not be negative", errListenerConfigInvalid)
+	case lc.AcceptLoops < 0:
+		return fmt.Errorf("%w: acceptLoops must not be negative", errListenerConfigInvalid)
+	case lc.Backlog < 0:
+		return fmt.Errorf("%w: backlog must not be negative", errListenerConfigInvalid)
+	case lc.AcceptLoops > 1 && !lc.ReusePort:
+		return errAcceptLoopsRequireReusePort
+	case !socketOptionsSupported && lc.ReusePort:
+		return fmt.Errorf("%w: reusePort", errSocketOptionsNotSupported)
+	case !socketOptionsSupported && lc.Backlog > 0:
+		return fmt.Errorf("%w: backlog", errSocketOptionsNotSupported)
+	}
+
+	return nil
+}
+
+// GetAcceptLoops returns the number of listeners that are opened for every TCP address. Default is 1.
+func (lc ListenerConfig) GetAcceptLoops() int {
+	if lc.AcceptLoops > 1 {
+		return lc.AcceptLoops
+	}
+
+	return 1
+}
+
+// IsNoDelay checks if TCP_NODELAY is set on accepted connections. Default is true.
+func (lc ListenerConfig) IsNoDelay() bool {
+	return lc.NoDelay == nil || *lc.NoDelay
+}
+
+// newListenConfig creates the config to listen with the socket options.
+func (lc ListenerConfig) newListenConfig() net.ListenConfig {
+	listenConfig := net.ListenConfig{
+		KeepAliveConfig: net.KeepAliveConfig{
+			Enable:   !lc.DisableKeepAlive,
+			Idle:     time.Duration(lc.KeepAliveIdle),
+			Interval: time.Duration(lc.KeepAliveInterval),
+			Count:    lc.KeepAliveCount,
+		},
+	}
+
+	if lc.DisableKeepAlive {
+		listenConfig.KeepAlive = -1
+	}
+
+	if lc.ReusePort {
+		listenConfig.Control = controlReusePort
+	}
+
+	return listenConfig
+}
+
+// noDelayListener disables TCP_NODELAY on accepted connections that Go enables by default.
+type noDelayListener struct {
+	net.Listener
+}
+
+// Accept waits for and returns the next connection with Nagle's algorithm enabled.
+func (ndl noDelayListener) Accept() (net.Conn, error) {
+	conn, err := ndl.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		_ = tcpConn.SetNoDelay(false)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package gohttps
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/relychan/goutils"
+	"golang.org/x/sys/unix"
+)
+
+func TestListenerConfig_SocketOptions(t *testing.T) {
+	noDelay := false
+	config := ListenerConfig{
+		KeepAliveIdle:     goutils.Duration(30 * time.Second),
+		KeepAliveInterval: goutils.Duration(10 * time.Second),
+		KeepAliveCount:    3,
+		NoDelay:           &noDelay,
+		ReusePort:         true,
+		Backlog:           16,
+	}
+
+	if err := config.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	listenConfig := config.newListenConfig()
+
+	listener, err := listenConfig.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	t.Run("reuse port", func(t *testing.T) {
+		if value := getTestSocketOption(t, listener.(syscall.Conn), unix.SOL_SOCKET, unix.SO_REUSEPORT); value != 1 {
+			t.Errorf("expected SO_REUSEPORT to be set, got %d", value)
+		}
+
+		// Another socket listens to the same address with SO_REUSEPORT.
+		other, err := listenConfig.Listen(context.Background(), "tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatalf("failed to listen to the same address: %v", err)
+		}
+
+		_ = other.Close()
+	})
+
+	t.Run("backlog", func(t *testing.T) {
+		if err := setListenBacklog(listener, config.Backlog); err != nil {
+			t.Errorf("failed to set backlog: %v", err)
+		}
+	})
+
+	t.Run("accepted connection", func(t *testing.T) {
+		client, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer client.Close()
+
+		conn, err := noDelayListener{Listener: listener}.Accept()
+		if err != nil {
+			t.Fatalf("failed to accept: %v", err)
+		}
+		defer conn.Close()
+
+		tcpConn := conn.(*net.TCPConn)
+
+		expected := map[string]struct {
+			level  int
+			option int
+			value  int
+		}{
+			"SO_KEEPALIVE":  {unix.SOL_SOCKET, unix.SO_KEEPALIVE, 1},
+			"TCP_KEEPIDLE":  {unix.IPPROTO_TCP, unix.TCP_KEEPIDLE, 30},
+			"TCP_KEEPINTVL": {unix.IPPROTO_TCP, unix.TCP_KEEPINTVL, 10},
+			"TCP_KEEPCNT":   {unix.IPPROTO_TCP, unix.TCP_KEEPCNT, 3},
+			"TCP_NODELAY":   {unix.IPPROTO_TCP, unix.TCP_NODELAY, 0},
+		}
+
+		for name, opt := range expected {
+			if value := getTestSocketOption(t, tcpConn, opt.level, opt.option); value != opt.value {
+				t.Errorf("expected %s to be %d, got %d", name, opt.value, value)
+			}
+		}
+	})
+}
+
+func TestListenerConfig_DisableKeepAlive(t *testing.T) {
+	listenConfig := ListenerConfig{DisableKeepAlive: true}.newListenConfig()
+
+	listener, err := listenConfig.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatalf("failed to accept: %v", err)
+	}
+	defer conn.Close()
+
+	if value := getTestSocketOption(t, conn.(*net.TCPConn), unix.SOL_SOCKET, unix.SO_KEEPALIVE); value != 0 {
+		t.Errorf("expected SO_KEEPALIVE to be disabled, got %d", value)
+	}
+
+	if value := getTestSocketOption(t, conn.(*net.TCPConn), unix.IPPROTO_TCP, unix.TCP_NODELAY); value != 1 {
+		t.Errorf("expected TCP_NODELAY to be enabled by default, got %d", value)
+	}
+}
+
+func TestServerAcceptLoops(t *testing.T) {
+	config := &ServerConfig{
+		Addresses: []ListenAddress{{Address: "127.0.0.1:0"}},
+		Listener: &ListenerConfig{
+			ReusePort:   true,
+			AcceptLoops: 3,
+		},
+	}
+
+	server, err := NewServer(config, NewRouter(config, slog.Default()))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = server.Shutdown(context.Background())
+	})
+
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+
+	addrs := server.Addrs()
+	if len(addrs) != 3 {
+		t.Fatalf("expected 3 listeners, got %d", len(addrs))
+	}
+
+	for _, addr := range addrs[1:] {
+		if addr.String() != addrs[0].String() {
+			t.Errorf("expected all listeners on %s, got %s", addrs[0], addr)
+		}
+	}
+
+	waitForStatus(t, "http://"+addrs[0].String()+pathHealthz, http.StatusOK)
+}
+
+func getTestSocketOption(t *testing.T, conn syscall.Conn, level, option int) int {
+	t.Helper()
+
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		t.Fatalf("failed to get the raw connection: %v", err)
+	}
+
+	var value int
+
+	var sockErr error
+
+	err = rawConn.Control(func(fd uintptr) {
+		value, sockErr = unix.GetsockoptInt(int(fd), level, option)
+	})
+	if err != nil {
+		t.Fatalf("failed to control the connection: %v", err)
+	}
+
+	if sockErr != nil {
+		t.Fatalf("failed to get the socket option: %v", sockErr)
+	}
+
+	return value
+}
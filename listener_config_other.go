@@ -0,0 +1,34 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+
+package gohttps
+
+import (
+	"net"
+	"syscall"
+)
+
+const socketOptionsSupported = false
+
+// controlReusePort is not supported on this platform. The config is validated when the server is created.
+func controlReusePort(_, _ string, _ syscall.RawConn) error {
+	return errSocketOptionsNotSupported
+}
+
+// setListenBacklog is not supported on this platform. The config is validated when the server is created.
+func setListenBacklog(_ net.Listener, _ int) error {
+	return nil
+}
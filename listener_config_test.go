@@ -0,0 +1,95 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/relychan/goutils"
+)
+
+func TestListenerConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   ListenerConfig
+		expected error
+	}{
+		{
+			name:   "empty",
+			config: ListenerConfig{},
+		},
+		{
+			name: "keep_alive",
+			config: ListenerConfig{
+				KeepAliveIdle:     goutils.Duration(30 * time.Second),
+				KeepAliveInterval: goutils.Duration(10 * time.Second),
+				KeepAliveCount:    3,
+			},
+		},
+		{
+			name:     "negative_keep_alive_count",
+			config:   ListenerConfig{KeepAliveCount: -1},
+			expected: errListenerConfigInvalid,
+		},
+		{
+			name:     "negative_accept_loops",
+			config:   ListenerConfig{AcceptLoops: -1},
+			expected: errListenerConfigInvalid,
+		},
+		{
+			name:     "negative_backlog",
+			config:   ListenerConfig{Backlog: -1},
+			expected: errListenerConfigInvalid,
+		},
+		{
+			name:     "accept_loops_without_reuse_port",
+			config:   ListenerConfig{AcceptLoops: 4},
+			expected: errAcceptLoopsRequireReusePort,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestListenerConfig_Defaults(t *testing.T) {
+	config := ServerConfig{}.GetListenerConfig()
+
+	if loops := config.GetAcceptLoops(); loops != 1 {
+		t.Errorf("expected 1 accept loop, got %d", loops)
+	}
+
+	if !config.IsNoDelay() {
+		t.Error("expected TCP_NODELAY to be enabled by default")
+	}
+
+	listenConfig := config.newListenConfig()
+
+	if !listenConfig.KeepAliveConfig.Enable {
+		t.Error("expected keep-alive to be enabled by default")
+	}
+
+	if listenConfig.Control != nil {
+		t.Error("expected no socket control by default")
+	}
+}
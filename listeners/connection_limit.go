@@ -0,0 +1,414 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listeners
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/relychan/goutils"
+)
+
+const (
+	defaultConnectionLimitQueueTimeout   = 10 * time.Second
+	defaultConnectionLimitMaxQueueLength = 128
+)
+
+var (
+	errConnectionLimitInvalid     = errors.New("invalid connection limit")
+	errConnectionLimitModeInvalid = errors.New("invalid connection limit mode")
+)
+
+// ConnectionLimitMode represents the enum of behaviors for connections over the limit.
+type ConnectionLimitMode string
+
+const (
+	// ConnectionLimitModeReject closes connections over the limit immediately.
+	ConnectionLimitModeReject ConnectionLimitMode = "reject"
+	// ConnectionLimitModeQueue holds connections over the limit until a slot is free or the queue timeout elapses.
+	ConnectionLimitModeQueue ConnectionLimitMode = "queue"
+)
+
+// ConnectionLimitReason represents the enum of limits that reject connections.
+type ConnectionLimitReason string
+
+const (
+	// ConnectionLimitReasonMaxConnections indicates that the global connection limit is reached.
+	ConnectionLimitReasonMaxConnections ConnectionLimitReason = "max_connections"
+	// ConnectionLimitReasonMaxConnectionsPerIP indicates that the connection limit of the remote IP is reached.
+	ConnectionLimitReasonMaxConnectionsPerIP ConnectionLimitReason = "max_connections_per_ip"
+	// ConnectionLimitReasonQueueFull indicates that the queue of connections over the limit is full.
+	ConnectionLimitReasonQueueFull ConnectionLimitReason = "queue_full"
+)
+
+// ConnectionLimitConfig represents the configuration of concurrent connection limits.
+// Limits apply to the peer address of TCP connections, e.g. the load balancer if the PROXY protocol is used.
+type ConnectionLimitConfig struct {
+	// The maximum number of concurrent connections across all listeners. Unlimited if zero.
+	MaxConnections int `env:"SERVER_CONNECTION_LIMIT_MAX_CONNECTIONS" json:"maxConnections,omitempty" yaml:"maxConnections,omitempty" jsonschema:"minimum=0"`
+	// The maximum number of concurrent connections from a remote IP. Unlimited if zero.
+	MaxConnectionsPerIP int `env:"SERVER_CONNECTION_LIMIT_MAX_CONNECTIONS_PER_IP" json:"maxConnectionsPerIp,omitempty" yaml:"maxConnectionsPerIp,omitempty" jsonschema:"minimum=0"`
+	// The behavior for connections over the limit: reject closes them immediately,
+	// queue holds them until a slot is free or the queue timeout elapses. Default is reject.
+	Mode ConnectionLimitMode `env:"SERVER_CONNECTION_LIMIT_MODE" json:"mode,omitempty" yaml:"mode,omitempty" jsonschema:"enum=reject,enum=queue"`
+	// The maximum duration that a connection waits in the queue before it is rejected. Default is 10 seconds.
+	QueueTimeout goutils.Duration `env:"SERVER_CONNECTION_LIMIT_QUEUE_TIMEOUT" json:"queueTimeout,omitempty" yaml:"queueTimeout,omitempty"`
+	// The maximum number of connections that wait in the queue across all listeners.
+	// Connections are rejected immediately once the queue is full. Default is 128.
+	MaxQueueLength int `env:"SERVER_CONNECTION_LIMIT_MAX_QUEUE_LENGTH" json:"maxQueueLength,omitempty" yaml:"maxQueueLength,omitempty" jsonschema:"minimum=0"`
+}
+
+// Validate checks if the configuration is valid.
+func (clc ConnectionLimitConfig) Validate() error {
+	switch {
+	case clc.MaxConnections < 0:
+		return fmt.Errorf("%w: maxConnections must not be negative", errConnectionLimitInvalid)
+	case clc.MaxConnectionsPerIP < 0:
+		return fmt.Errorf("%w: maxConnectionsPerIp must not be negative", errConnectionLimitInvalid)
+	case clc.QueueTimeout < 0:
+		return fmt.Errorf("%w: queueTimeout must not be negative", errConnectionLimitInvalid)
+	case clc.MaxQueueLength < 0:
+		return fmt.Errorf("%w: maxQueueLength must not be negative", errConnectionLimitInvalid)
+	}
+
+	switch clc.Mode {
+	case "", ConnectionLimitModeReject, ConnectionLimitModeQueue:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", errConnectionLimitModeInvalid, clc.Mode)
+	}
+}
+
+// GetQueueTimeout returns the maximum duration that a connection waits in the queue. Default is 10 seconds.
+func (clc ConnectionLimitConfig) GetQueueTimeout() time.Duration {
+	if clc.QueueTimeout > 0 {
+		return time.Duration(clc.QueueTimeout)
+	}
+
+	return defaultConnectionLimitQueueTimeout
+}
+
+// GetMaxQueueLength returns the maximum number of connections that wait in the queue. Default is 128.
+func (clc ConnectionLimitConfig) GetMaxQueueLength() int {
+	if clc.MaxQueueLength > 0 {
+		return clc.MaxQueueLength
+	}
+
+	return defaultConnectionLimitMaxQueueLength
+}
+
+// IsEnabled checks if any connection limit is configured.
+func (clc ConnectionLimitConfig) IsEnabled() bool {
+	return clc.MaxConnections > 0 || clc.MaxConnectionsPerIP > 0
+}
+
+// ConnectionLimiter counts concurrent connections of the listeners that it wraps
+// and enforces the global and per-IP limits.
+type ConnectionLimiter struct {
+	maxConnections      int
+	maxConnectionsPerIP int
+	queue               bool
+	queueTimeout        time.Duration
+	maxQueueLength      int
+	onReject            func(reason ConnectionLimitReason)
+
+	mu     sync.Mutex
+	active int
+	queued int
+	perIP  map[netip.Addr]int
+	// closed and replaced whenever a connection is released to wake up queued connections.
+	released chan struct{}
+}
+
+// NewConnectionLimiter creates a connection limiter from the config.
+// The optional onReject callback is called for every rejected connection, e.g. to record a metric.
+func NewConnectionLimiter(
+	config ConnectionLimitConfig,
+	onReject func(reason ConnectionLimitReason),
+) (*ConnectionLimiter, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConnectionLimiter{
+		maxConnections:      config.MaxConnections,
+		maxConnectionsPerIP: config.MaxConnectionsPerIP,
+		queue:               config.Mode == ConnectionLimitModeQueue,
+		queueTimeout:        config.GetQueueTimeout(),
+		maxQueueLength:      config.GetMaxQueueLength(),
+		onReject:            onReject,
+		perIP:               map[netip.Addr]int{},
+		released:            make(chan struct{}),
+	}, nil
+}
+
+// ActiveConnections returns the number of open connections that count towards the limits.
+func (cl *ConnectionLimiter) ActiveConnections() int {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	return cl.active
+}
+
+// Listen wraps the listener to enforce the limits on accepted connections.
+func (cl *ConnectionLimiter) Listen(listener net.Listener) *ConnectionLimitListener {
+	return &ConnectionLimitListener{
+		Listener: listener,
+		limiter:  cl,
+		results:  make(chan net.Conn),
+		errs:     make(chan error),
+		closed:   make(chan struct{}),
+	}
+}
+
+// tryAcquire takes a slot for the IP if no limit is reached. Otherwise, it returns the reached limit.
+func (cl *ConnectionLimiter) tryAcquire(ip netip.Addr) (ConnectionLimitReason, <-chan struct{}, bool) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if cl.maxConnections > 0 && cl.active >= cl.maxConnections {
+		return ConnectionLimitReasonMaxConnections, cl.released, false
+	}
+
+	if cl.maxConnectionsPerIP > 0 && ip.IsValid() && cl.perIP[ip] >= cl.maxConnectionsPerIP {
+		return ConnectionLimitReasonMaxConnectionsPerIP, cl.released, false
+	}
+
+	cl.active++
+
+	if ip.IsValid() {
+		cl.perIP[ip]++
+	}
+
+	return "", nil, true
+}
+
+// acquire takes a slot for the IP. In queue mode, it waits until a slot is free,
+// the queue timeout elapses or the stop channel is closed.
+func (cl *ConnectionLimiter) acquire(ip netip.Addr, stop <-chan struct{}) (ConnectionLimitReason, bool) {
+	reason, released, ok := cl.tryAcquire(ip)
+	if ok || !cl.queue {
+		return reason, ok
+	}
+
+	timer := time.NewTimer(cl.queueTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-released:
+		case <-timer.C:
+			return reason, false
+		case <-stop:
+			return reason, false
+		}
+
+		reason, released, ok = cl.tryAcquire(ip)
+		if ok {
+			return reason, true
+		}
+	}
+}
+
+// enqueue takes a place in the queue. Returns false if the queue is full.
+func (cl *ConnectionLimiter) enqueue() bool {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if cl.queued >= cl.maxQueueLength {
+		return false
+	}
+
+	cl.queued++
+
+	return true
+}
+
+func (cl *ConnectionLimiter) dequeue() {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	cl.queued--
+}
+
+func (cl *ConnectionLimiter) release(ip netip.Addr) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	cl.active--
+
+	if ip.IsValid() {
+		cl.perIP[ip]--
+
+		if cl.perIP[ip] <= 0 {
+			delete(cl.perIP, ip)
+		}
+	}
+
+	close(cl.released)
+	cl.released = make(chan struct{})
+}
+
+func (cl *ConnectionLimiter) reject(conn net.Conn, reason ConnectionLimitReason) {
+	slog.Debug(
+		"connection rejected by the connection limit",
+		slog.String("remote_addr", conn.RemoteAddr().String()),
+		slog.String("reason", string(reason)),
+	)
+
+	if cl.onReject != nil {
+		cl.onReject(reason)
+	}
+
+	_ = conn.Close()
+}
+
+// ConnectionLimitListener wraps a listener to enforce the limits of the connection limiter on accepted connections.
+// Connections are accepted in the background so connections over the limit do not block the others.
+type ConnectionLimitListener struct {
+	net.Listener
+
+	limiter   *ConnectionLimiter
+	startOnce sync.Once
+	closeOnce sync.Once
+	results   chan net.Conn
+	errs      chan error
+	closed    chan struct{}
+}
+
+// Accept waits for and returns the next connection within the limits.
+func (cll *ConnectionLimitListener) Accept() (net.Conn, error) {
+	cll.startOnce.Do(func() {
+		go cll.acceptLoop()
+	})
+
+	select {
+	case conn := <-cll.results:
+		return conn, nil
+	case err := <-cll.errs:
+		return nil, err
+	case <-cll.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close closes the listener. Queued connections are rejected.
+func (cll *ConnectionLimitListener) Close() error {
+	cll.closeOnce.Do(func() {
+		close(cll.closed)
+	})
+
+	return cll.Listener.Close()
+}
+
+func (cll *ConnectionLimitListener) acceptLoop() {
+	for {
+		conn, err := cll.Listener.Accept()
+		if err != nil {
+			select {
+			case cll.errs <- err:
+			case <-cll.closed:
+				return
+			}
+
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			continue
+		}
+
+		ip := remoteIP(conn.RemoteAddr())
+
+		reason, _, ok := cll.limiter.tryAcquire(ip)
+
+		switch {
+		case ok:
+			cll.deliver(conn, ip)
+		case !cll.limiter.queue:
+			cll.limiter.reject(conn, reason)
+		case cll.limiter.enqueue():
+			go cll.enqueue(conn, ip)
+		default:
+			cll.limiter.reject(conn, ConnectionLimitReasonQueueFull)
+		}
+	}
+}
+
+// enqueue waits for a free slot and delivers the connection, or rejects it once the queue timeout elapses.
+func (cll *ConnectionLimitListener) enqueue(conn net.Conn, ip netip.Addr) {
+	reason, ok := cll.limiter.acquire(ip, cll.closed)
+
+	cll.limiter.dequeue()
+
+	if !ok {
+		cll.limiter.reject(conn, reason)
+
+		return
+	}
+
+	cll.deliver(conn, ip)
+}
+
+// deliver hands the connection that holds a slot over to Accept.
+func (cll *ConnectionLimitListener) deliver(conn net.Conn, ip netip.Addr) {
+	limitedConn := &connectionLimitConn{
+		Conn:    conn,
+		limiter: cll.limiter,
+		ip:      ip,
+	}
+
+	select {
+	case cll.results <- limitedConn:
+	case <-cll.closed:
+		_ = limitedConn.Close()
+	}
+}
+
+// connectionLimitConn releases the slot of the connection limiter when the connection is closed.
+type connectionLimitConn struct {
+	net.Conn
+
+	limiter *ConnectionLimiter
+	ip      netip.Addr
+	once    sync.Once
+}
+
+// Close closes the connection and releases its slot.
+func (clc *connectionLimitConn) Close() error {
+	err := clc.Conn.Close()
+
+	clc.once.Do(func() {
+		clc.limiter.release(clc.ip)
+	})
+
+	return err
+}
+
+// remoteIP returns the IP of a TCP address. Returns an invalid address for other networks.
+func remoteIP(addr net.Addr) netip.Addr {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return netip.Addr{}
+	}
+
+	return tcpAddr.AddrPort().Addr().Unmap()
+}
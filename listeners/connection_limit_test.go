@@ -0,0 +1,301 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listeners
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/relychan/goutils"
+)
+
+func TestConnectionLimitConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   ConnectionLimitConfig
+		expected error
+	}{
+		{
+			name:   "empty",
+			config: ConnectionLimitConfig{},
+		},
+		{
+			name: "queue",
+			config: ConnectionLimitConfig{
+				MaxConnections:      100,
+				MaxConnectionsPerIP: 10,
+				Mode:                ConnectionLimitModeQueue,
+				QueueTimeout:        goutils.Duration(time.Second),
+			},
+		},
+		{
+			name:     "negative_max_connections",
+			config:   ConnectionLimitConfig{MaxConnections: -1},
+			expected: errConnectionLimitInvalid,
+		},
+		{
+			name:     "negative_max_connections_per_ip",
+			config:   ConnectionLimitConfig{MaxConnectionsPerIP: -1},
+			expected: errConnectionLimitInvalid,
+		},
+		{
+			name:     "negative_queue_timeout",
+			config:   ConnectionLimitConfig{QueueTimeout: goutils.Duration(-time.Second)},
+			expected: errConnectionLimitInvalid,
+		},
+		{
+			name:     "negative_max_queue_length",
+			config:   ConnectionLimitConfig{MaxQueueLength: -1},
+			expected: errConnectionLimitInvalid,
+		},
+		{
+			name:     "invalid_mode",
+			config:   ConnectionLimitConfig{Mode: "drop"},
+			expected: errConnectionLimitModeInvalid,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestConnectionLimitListener(t *testing.T) {
+	t.Run("rejects connections over the per-IP limit", func(t *testing.T) {
+		listener, rejected := startTestConnectionLimitListener(t, ConnectionLimitConfig{MaxConnectionsPerIP: 1})
+
+		first := dialTestConnection(t, listener)
+		accepted := acceptTestConnection(t, listener)
+
+		second := dialTestConnection(t, listener)
+
+		if reason := waitForTestRejection(t, rejected); reason != ConnectionLimitReasonMaxConnectionsPerIP {
+			t.Errorf("expected reason %s, got %s", ConnectionLimitReasonMaxConnectionsPerIP, reason)
+		}
+
+		assertTestConnectionClosed(t, second)
+
+		if active := listener.limiter.ActiveConnections(); active != 1 {
+			t.Errorf("expected 1 active connection, got %d", active)
+		}
+
+		_ = accepted.Close()
+		_ = first.Close()
+
+		if active := listener.limiter.ActiveConnections(); active != 0 {
+			t.Errorf("expected no active connection, got %d", active)
+		}
+
+		dialTestConnection(t, listener)
+		acceptTestConnection(t, listener)
+	})
+
+	t.Run("rejects connections over the global limit", func(t *testing.T) {
+		listener, rejected := startTestConnectionLimitListener(t, ConnectionLimitConfig{
+			MaxConnections:      1,
+			MaxConnectionsPerIP: 10,
+		})
+
+		dialTestConnection(t, listener)
+		acceptTestConnection(t, listener)
+		dialTestConnection(t, listener)
+
+		if reason := waitForTestRejection(t, rejected); reason != ConnectionLimitReasonMaxConnections {
+			t.Errorf("expected reason %s, got %s", ConnectionLimitReasonMaxConnections, reason)
+		}
+	})
+
+	t.Run("queues connections until a slot is free", func(t *testing.T) {
+		listener, rejected := startTestConnectionLimitListener(t, ConnectionLimitConfig{
+			MaxConnections: 1,
+			Mode:           ConnectionLimitModeQueue,
+			QueueTimeout:   goutils.Duration(5 * time.Second),
+		})
+
+		dialTestConnection(t, listener)
+		accepted := acceptTestConnection(t, listener)
+		dialTestConnection(t, listener)
+
+		queued := make(chan net.Conn, 1)
+
+		go func() {
+			conn, err := listener.Accept()
+			if err == nil {
+				queued <- conn
+			}
+		}()
+
+		select {
+		case <-queued:
+			t.Fatal("expected the connection to be queued")
+		case reason := <-rejected:
+			t.Fatalf("expected the connection to be queued, got rejected by %s", reason)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		_ = accepted.Close()
+
+		select {
+		case conn := <-queued:
+			_ = conn.Close()
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the queued connection to be accepted")
+		}
+	})
+
+	t.Run("rejects queued connections after the timeout", func(t *testing.T) {
+		listener, rejected := startTestConnectionLimitListener(t, ConnectionLimitConfig{
+			MaxConnections: 1,
+			Mode:           ConnectionLimitModeQueue,
+			QueueTimeout:   goutils.Duration(50 * time.Millisecond),
+		})
+
+		dialTestConnection(t, listener)
+		acceptTestConnection(t, listener)
+		second := dialTestConnection(t, listener)
+
+		if reason := waitForTestRejection(t, rejected); reason != ConnectionLimitReasonMaxConnections {
+			t.Errorf("expected reason %s, got %s", ConnectionLimitReasonMaxConnections, reason)
+		}
+
+		assertTestConnectionClosed(t, second)
+	})
+
+	t.Run("rejects connections once the queue is full", func(t *testing.T) {
+		listener, rejected := startTestConnectionLimitListener(t, ConnectionLimitConfig{
+			MaxConnections: 1,
+			Mode:           ConnectionLimitModeQueue,
+			QueueTimeout:   goutils.Duration(5 * time.Second),
+			MaxQueueLength: 1,
+		})
+
+		dialTestConnection(t, listener)
+		acceptTestConnection(t, listener)
+		dialTestConnection(t, listener)
+		third := dialTestConnection(t, listener)
+
+		if reason := waitForTestRejection(t, rejected); reason != ConnectionLimitReasonQueueFull {
+			t.Errorf("expected reason %s, got %s", ConnectionLimitReasonQueueFull, reason)
+		}
+
+		assertTestConnectionClosed(t, third)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		listener, _ := startTestConnectionLimitListener(t, ConnectionLimitConfig{MaxConnections: 1})
+
+		_ = listener.Close()
+
+		_, err := listener.Accept()
+		if !errors.Is(err, net.ErrClosed) {
+			t.Errorf("expected net.ErrClosed, got %v", err)
+		}
+	})
+}
+
+func startTestConnectionLimitListener(
+	t *testing.T,
+	config ConnectionLimitConfig,
+) (*ConnectionLimitListener, chan ConnectionLimitReason) {
+	t.Helper()
+
+	rejected := make(chan ConnectionLimitReason, 10)
+
+	limiter, err := NewConnectionLimiter(config, func(reason ConnectionLimitReason) {
+		rejected <- reason
+	})
+	if err != nil {
+		t.Fatalf("failed to create connection limiter: %v", err)
+	}
+
+	rawListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	listener := limiter.Listen(rawListener)
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	return listener, rejected
+}
+
+func dialTestConnection(t *testing.T, listener net.Listener) net.Conn {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return conn
+}
+
+func acceptTestConnection(t *testing.T, listener net.Listener) net.Conn {
+	t.Helper()
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatalf("failed to accept: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return conn
+}
+
+func waitForTestRejection(t *testing.T, rejected chan ConnectionLimitReason) ConnectionLimitReason {
+	t.Helper()
+
+	select {
+	case reason := <-rejected:
+		return reason
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the connection to be rejected")
+
+		return ""
+	}
+}
+
+func assertTestConnectionClosed(t *testing.T, conn net.Conn) {
+	t.Helper()
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	_, err := conn.Read(make([]byte, 1))
+	if err == nil {
+		t.Error("expected the connection to be closed")
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		t.Errorf("expected the connection to be closed, got %v", err)
+	}
+}
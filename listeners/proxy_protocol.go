@@ -0,0 +1,241 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package listeners implements wrappers of network listeners.
+package listeners
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/relychan/goutils"
+)
+
+const defaultProxyProtocolHeaderTimeout = 10 * time.Second
+
+var errProxyProtocolTrustedIPPrefixInvalid = errors.New("invalid trusted IP prefix of the PROXY protocol")
+
+type proxyHeaderContextKey struct{}
+
+// ProxyProtocolConfig represents the configuration of the PROXY protocol v1 and v2 that load balancers,
+// e.g. AWS NLB or HAProxy in TCP mode, use to pass the address of the client.
+// The remote address of connections is rewritten to the address of the client.
+type ProxyProtocolConfig struct {
+	// List of CIDR prefixes of load balancers that are trusted to send the PROXY protocol header.
+	// Connections from trusted sources must start with the header. Connections from other sources are served as is.
+	// All sources are trusted if empty.
+	TrustedIPPrefixes []string `env:"SERVER_PROXY_PROTOCOL_TRUSTED_IP_PREFIXES" json:"trustedIpPrefixes,omitempty" yaml:"trustedIpPrefixes,omitempty"`
+	// The maximum duration to read the PROXY protocol header. Default is 10 seconds.
+	HeaderTimeout goutils.Duration `env:"SERVER_PROXY_PROTOCOL_HEADER_TIMEOUT" json:"headerTimeout,omitempty" yaml:"headerTimeout,omitempty"`
+}
+
+// Validate checks if the configuration is valid.
+func (ppc ProxyProtocolConfig) Validate() error {
+	_, err := ppc.parseTrustedIPPrefixes()
+
+	return err
+}
+
+// GetHeaderTimeout returns the maximum duration to read the PROXY protocol header. Default is 10 seconds.
+func (ppc ProxyProtocolConfig) GetHeaderTimeout() time.Duration {
+	if ppc.HeaderTimeout > 0 {
+		return time.Duration(ppc.HeaderTimeout)
+	}
+
+	return defaultProxyProtocolHeaderTimeout
+}
+
+func (ppc ProxyProtocolConfig) parseTrustedIPPrefixes() ([]netip.Prefix, error) {
+	results := make([]netip.Prefix, len(ppc.TrustedIPPrefixes))
+
+	for i, rawPrefix := range ppc.TrustedIPPrefixes {
+		prefix, err := netip.ParsePrefix(rawPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errProxyProtocolTrustedIPPrefixInvalid, err)
+		}
+
+		results[i] = prefix.Masked()
+	}
+
+	return results, nil
+}
+
+// ProxyProtocolListener wraps a listener to read the PROXY protocol header of accepted connections.
+type ProxyProtocolListener struct {
+	net.Listener
+
+	trustedIPPrefixes []netip.Prefix
+	headerTimeout     time.Duration
+}
+
+// NewProxyProtocolListener creates a listener that reads the PROXY protocol header of accepted connections.
+func NewProxyProtocolListener(listener net.Listener, config ProxyProtocolConfig) (*ProxyProtocolListener, error) {
+	trustedIPPrefixes, err := config.parseTrustedIPPrefixes()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProxyProtocolListener{
+		Listener:          listener,
+		trustedIPPrefixes: trustedIPPrefixes,
+		headerTimeout:     config.GetHeaderTimeout(),
+	}, nil
+}
+
+// Accept waits for and returns the next connection. The header is read lazily on the first read or
+// address lookup of the connection, so a slow client does not block the accept loop.
+func (ppl *ProxyProtocolListener) Accept() (net.Conn, error) {
+	conn, err := ppl.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	if !ppl.isTrusted(conn.RemoteAddr()) {
+		return conn, nil
+	}
+
+	return &ProxyProtocolConn{
+		Conn:          conn,
+		reader:        bufio.NewReader(conn),
+		headerTimeout: ppl.headerTimeout,
+	}, nil
+}
+
+func (ppl *ProxyProtocolListener) isTrusted(addr net.Addr) bool {
+	if len(ppl.trustedIPPrefixes) == 0 {
+		return true
+	}
+
+	ip := remoteIP(addr)
+	if !ip.IsValid() {
+		return false
+	}
+
+	for _, prefix := range ppl.trustedIPPrefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ProxyProtocolConn represents a connection that starts with the PROXY protocol header.
+type ProxyProtocolConn struct {
+	net.Conn
+
+	reader        *bufio.Reader
+	headerTimeout time.Duration
+	once          sync.Once
+	header        *ProxyHeader
+	err           error
+}
+
+// Read reads data from the connection after the PROXY protocol header.
+func (ppc *ProxyProtocolConn) Read(b []byte) (int, error) {
+	_, err := ppc.ProxyHeader()
+	if err != nil {
+		return 0, err
+	}
+
+	return ppc.reader.Read(b)
+}
+
+// RemoteAddr returns the address of the client in the PROXY protocol header.
+// Falls back to the remote address of the connection if the header does not contain addresses.
+func (ppc *ProxyProtocolConn) RemoteAddr() net.Addr {
+	header, err := ppc.ProxyHeader()
+	if err != nil || header.SourceAddr == nil {
+		return ppc.Conn.RemoteAddr()
+	}
+
+	return header.SourceAddr
+}
+
+// LocalAddr returns the address that the client connected to in the PROXY protocol header.
+// Falls back to the local address of the connection if the header does not contain addresses.
+func (ppc *ProxyProtocolConn) LocalAddr() net.Addr {
+	header, err := ppc.ProxyHeader()
+	if err != nil || header.DestinationAddr == nil {
+		return ppc.Conn.LocalAddr()
+	}
+
+	return header.DestinationAddr
+}
+
+// ProxyHeader reads and returns the PROXY protocol header of the connection.
+func (ppc *ProxyProtocolConn) ProxyHeader() (*ProxyHeader, error) {
+	ppc.once.Do(func() {
+		ppc.err = ppc.Conn.SetReadDeadline(time.Now().Add(ppc.headerTimeout))
+		if ppc.err != nil {
+			return
+		}
+
+		ppc.header, ppc.err = readProxyHeader(ppc.reader)
+		if ppc.err != nil {
+			slog.Debug(
+				"failed to read the PROXY protocol header",
+				slog.String("remote_addr", ppc.Conn.RemoteAddr().String()),
+				slog.String("error", ppc.err.Error()),
+			)
+
+			// Nothing must be written to a connection of an untrusted client.
+			_ = ppc.Conn.Close()
+
+			return
+		}
+
+		ppc.err = ppc.Conn.SetReadDeadline(time.Time{})
+	})
+
+	return ppc.header, ppc.err
+}
+
+// WithProxyHeaderContext stores the connection in the context so handlers can read the PROXY protocol header.
+// It has the signature of the ConnContext hook of http.Server.
+func WithProxyHeaderContext(ctx context.Context, conn net.Conn) context.Context {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+
+	ppc, ok := conn.(*ProxyProtocolConn)
+	if !ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, proxyHeaderContextKey{}, ppc)
+}
+
+// ProxyHeaderFromContext returns the PROXY protocol header of the connection that the request was received from.
+func ProxyHeaderFromContext(ctx context.Context) (*ProxyHeader, bool) {
+	ppc, ok := ctx.Value(proxyHeaderContextKey{}).(*ProxyProtocolConn)
+	if !ok {
+		return nil, false
+	}
+
+	header, err := ppc.ProxyHeader()
+	if err != nil {
+		return nil, false
+	}
+
+	return header, true
+}
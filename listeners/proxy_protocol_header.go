@@ -0,0 +1,334 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listeners
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+var (
+	errProxyProtocolHeaderInvalid  = errors.New("invalid PROXY protocol header")
+	errProxyProtocolVersionInvalid = errors.New("unsupported PROXY protocol version")
+	errProxyProtocolTLVInvalid     = errors.New("invalid PROXY protocol TLV")
+)
+
+// The maximum length of a PROXY protocol v1 header, including the CRLF.
+const proxyProtocolV1MaxLength = 107
+
+var (
+	proxyProtocolV1Signature = []byte("PROXY ")
+	proxyProtocolV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+// ProxyCommand represents the command of a PROXY protocol header.
+type ProxyCommand byte
+
+const (
+	// ProxyCommandLocal indicates the connection was established on purpose by the proxy without being relayed,
+	// e.g. health checks. The original addresses of the connection are used.
+	ProxyCommandLocal ProxyCommand = 0x0
+	// ProxyCommandProxy indicates the connection was relayed on behalf of another node.
+	ProxyCommandProxy ProxyCommand = 0x1
+)
+
+// ProxyTLVType represents the type of a PROXY protocol v2 TLV (type-length-value) vector.
+type ProxyTLVType byte
+
+// Registered TLV types of the PROXY protocol v2 specification.
+const (
+	ProxyTLVTypeALPN      ProxyTLVType = 0x01
+	ProxyTLVTypeAuthority ProxyTLVType = 0x02
+	ProxyTLVTypeCRC32C    ProxyTLVType = 0x03
+	ProxyTLVTypeNoop      ProxyTLVType = 0x04
+	ProxyTLVTypeUniqueID  ProxyTLVType = 0x05
+	ProxyTLVTypeSSL       ProxyTLVType = 0x20
+	ProxyTLVTypeNetNS     ProxyTLVType = 0x30
+	// ProxyTLVTypeAWS is the custom TLV type of AWS Network Load Balancers that carries the VPC endpoint ID.
+	ProxyTLVTypeAWS ProxyTLVType = 0xEA
+)
+
+// ProxyTLV represents a TLV (type-length-value) vector of the PROXY protocol v2 header.
+type ProxyTLV struct {
+	Type  ProxyTLVType
+	Value []byte
+}
+
+// ProxyHeader represents a parsed PROXY protocol header.
+type ProxyHeader struct {
+	// The version of the PROXY protocol, 1 or 2.
+	Version int
+	// The command of the header. Always ProxyCommandProxy in version 1.
+	Command ProxyCommand
+	// The address of the client. Nil if the addresses are unknown or unspecified.
+	SourceAddr net.Addr
+	// The address that the client connected to. Nil if the addresses are unknown or unspecified.
+	DestinationAddr net.Addr
+	// TLV vectors of the version 2 header.
+	TLVs []ProxyTLV
+}
+
+// TLV returns the value of the first TLV with the type.
+func (ph ProxyHeader) TLV(tlvType ProxyTLVType) ([]byte, bool) {
+	for _, tlv := range ph.TLVs {
+		if tlv.Type == tlvType {
+			return tlv.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// readProxyHeader reads a PROXY protocol v1 or v2 header from the reader.
+func readProxyHeader(reader *bufio.Reader) (*ProxyHeader, error) {
+	signature, err := reader.Peek(len(proxyProtocolV1Signature))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errProxyProtocolHeaderInvalid, err)
+	}
+
+	if bytes.Equal(signature, proxyProtocolV1Signature) {
+		return readProxyHeaderV1(reader)
+	}
+
+	signature, err = reader.Peek(len(proxyProtocolV2Signature))
+	if err != nil || !bytes.Equal(signature, proxyProtocolV2Signature) {
+		return nil, fmt.Errorf("%w: signature not found", errProxyProtocolHeaderInvalid)
+	}
+
+	return readProxyHeaderV2(reader)
+}
+
+// readProxyHeaderV1 reads the human-readable header, e.g. PROXY TCP4 192.0.2.1 198.51.100.1 56324 443\r\n.
+func readProxyHeaderV1(reader *bufio.Reader) (*ProxyHeader, error) {
+	line := make([]byte, 0, proxyProtocolV1MaxLength)
+
+	for !bytes.HasSuffix(line, []byte("\r\n")) {
+		if len(line) == proxyProtocolV1MaxLength {
+			return nil, fmt.Errorf("%w: header is too long", errProxyProtocolHeaderInvalid)
+		}
+
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errProxyProtocolHeaderInvalid, err)
+		}
+
+		line = append(line, b)
+	}
+
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	header := &ProxyHeader{
+		Version: 1,
+		Command: ProxyCommandProxy,
+	}
+
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return header, nil
+	}
+
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, fmt.Errorf("%w: %q", errProxyProtocolHeaderInvalid, line)
+	}
+
+	source, err := parseProxyAddrPortV1(fields[2], fields[4], fields[1] == "TCP4")
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := parseProxyAddrPortV1(fields[3], fields[5], fields[1] == "TCP4")
+	if err != nil {
+		return nil, err
+	}
+
+	header.SourceAddr = net.TCPAddrFromAddrPort(source)
+	header.DestinationAddr = net.TCPAddrFromAddrPort(destination)
+
+	return header, nil
+}
+
+func parseProxyAddrPortV1(rawAddr string, rawPort string, isIPv4 bool) (netip.AddrPort, error) {
+	addr, err := netip.ParseAddr(rawAddr)
+	if err != nil || addr.Is4() != isIPv4 || addr.Zone() != "" {
+		return netip.AddrPort{}, fmt.Errorf("%w: invalid address %s", errProxyProtocolHeaderInvalid, rawAddr)
+	}
+
+	// Ports must not have leading zeros.
+	port, err := strconv.ParseUint(rawPort, 10, 16)
+	if err != nil || (len(rawPort) > 1 && rawPort[0] == '0') {
+		return netip.AddrPort{}, fmt.Errorf("%w: invalid port %s", errProxyProtocolHeaderInvalid, rawPort)
+	}
+
+	return netip.AddrPortFrom(addr, uint16(port)), nil
+}
+
+// Address families and transport protocols of the PROXY protocol v2 header.
+const (
+	proxyProtocolV2FamilyUnspec = 0x0
+	proxyProtocolV2FamilyInet   = 0x1
+	proxyProtocolV2FamilyInet6  = 0x2
+	proxyProtocolV2FamilyUnix   = 0x3
+
+	proxyProtocolV2TransportStream = 0x1
+	proxyProtocolV2TransportDgram  = 0x2
+
+	proxyProtocolV2InetLength  = 12
+	proxyProtocolV2Inet6Length = 36
+	proxyProtocolV2UnixLength  = 216
+)
+
+// readProxyHeaderV2 reads the binary header.
+func readProxyHeaderV2(reader *bufio.Reader) (*ProxyHeader, error) {
+	// 12 bytes of signature, version and command, family and protocol, and 2 bytes of the length.
+	fixed := make([]byte, len(proxyProtocolV2Signature)+4)
+
+	_, err := io.ReadFull(reader, fixed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errProxyProtocolHeaderInvalid, err)
+	}
+
+	versionCommand := fixed[12]
+	if versionCommand>>4 != 0x2 {
+		return nil, fmt.Errorf("%w: %d", errProxyProtocolVersionInvalid, versionCommand>>4)
+	}
+
+	header := &ProxyHeader{
+		Version: 2,
+		Command: ProxyCommand(versionCommand & 0x0F),
+	}
+
+	if header.Command != ProxyCommandLocal && header.Command != ProxyCommandProxy {
+		return nil, fmt.Errorf("%w: unknown command %d", errProxyProtocolHeaderInvalid, header.Command)
+	}
+
+	family := fixed[13] >> 4
+	transport := fixed[13] & 0x0F
+	payload := make([]byte, binary.BigEndian.Uint16(fixed[14:16]))
+
+	_, err = io.ReadFull(reader, payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errProxyProtocolHeaderInvalid, err)
+	}
+
+	var addressLength int
+
+	switch family {
+	case proxyProtocolV2FamilyInet:
+		addressLength = proxyProtocolV2InetLength
+	case proxyProtocolV2FamilyInet6:
+		addressLength = proxyProtocolV2Inet6Length
+	case proxyProtocolV2FamilyUnix:
+		addressLength = proxyProtocolV2UnixLength
+	case proxyProtocolV2FamilyUnspec:
+	default:
+		return nil, fmt.Errorf("%w: unknown address family %d", errProxyProtocolHeaderInvalid, family)
+	}
+
+	if len(payload) < addressLength {
+		return nil, fmt.Errorf("%w: address block is too short", errProxyProtocolHeaderInvalid)
+	}
+
+	// Addresses are ignored by the receiver for the LOCAL command.
+	if header.Command == ProxyCommandProxy && addressLength > 0 {
+		header.SourceAddr, header.DestinationAddr, err = parseProxyAddressesV2(
+			family,
+			transport,
+			payload[:addressLength],
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	header.TLVs, err = parseProxyTLVs(payload[addressLength:])
+	if err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+func parseProxyAddressesV2(family byte, transport byte, block []byte) (net.Addr, net.Addr, error) {
+	if family == proxyProtocolV2FamilyUnix {
+		network := "unix"
+		if transport == proxyProtocolV2TransportDgram {
+			network = "unixgram"
+		}
+
+		return &net.UnixAddr{Net: network, Name: unixPathFromBytes(block[:108])},
+			&net.UnixAddr{Net: network, Name: unixPathFromBytes(block[108:])},
+			nil
+	}
+
+	ipLength := net.IPv4len
+	if family == proxyProtocolV2FamilyInet6 {
+		ipLength = net.IPv6len
+	}
+
+	sourceIP, _ := netip.AddrFromSlice(block[:ipLength])
+	destinationIP, _ := netip.AddrFromSlice(block[ipLength : 2*ipLength])
+	sourcePort := binary.BigEndian.Uint16(block[2*ipLength:])
+	destinationPort := binary.BigEndian.Uint16(block[2*ipLength+2:])
+
+	source := netip.AddrPortFrom(sourceIP, sourcePort)
+	destination := netip.AddrPortFrom(destinationIP, destinationPort)
+
+	switch transport {
+	case proxyProtocolV2TransportStream:
+		return net.TCPAddrFromAddrPort(source), net.TCPAddrFromAddrPort(destination), nil
+	case proxyProtocolV2TransportDgram:
+		return net.UDPAddrFromAddrPort(source), net.UDPAddrFromAddrPort(destination), nil
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown transport protocol %d", errProxyProtocolHeaderInvalid, transport)
+	}
+}
+
+func parseProxyTLVs(data []byte) ([]ProxyTLV, error) {
+	var results []ProxyTLV
+
+	for len(data) > 0 {
+		if len(data) < 3 {
+			return nil, fmt.Errorf("%w: truncated header", errProxyProtocolTLVInvalid)
+		}
+
+		length := int(binary.BigEndian.Uint16(data[1:3]))
+		if len(data) < 3+length {
+			return nil, fmt.Errorf("%w: truncated value of type 0x%02x", errProxyProtocolTLVInvalid, data[0])
+		}
+
+		results = append(results, ProxyTLV{
+			Type:  ProxyTLVType(data[0]),
+			Value: data[3 : 3+length],
+		})
+
+		data = data[3+length:]
+	}
+
+	return results, nil
+}
+
+func unixPathFromBytes(data []byte) string {
+	if index := bytes.IndexByte(data, 0); index >= 0 {
+		data = data[:index]
+	}
+
+	return string(data)
+}
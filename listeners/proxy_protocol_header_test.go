@@ -0,0 +1,265 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listeners
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestReadProxyHeaderV1(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		wantSource      string
+		wantDestination string
+		wantErr         error
+	}{
+		{
+			name:            "tcp4",
+			input:           "PROXY TCP4 192.0.2.1 198.51.100.1 56324 443\r\n",
+			wantSource:      "192.0.2.1:56324",
+			wantDestination: "198.51.100.1:443",
+		},
+		{
+			name:            "tcp6",
+			input:           "PROXY TCP6 2001:db8::1 2001:db8::2 56324 443\r\n",
+			wantSource:      "[2001:db8::1]:56324",
+			wantDestination: "[2001:db8::2]:443",
+		},
+		{
+			name:  "unknown",
+			input: "PROXY UNKNOWN ffff:f...f:ffff ffff:f...f:ffff 65535 65535\r\n",
+		},
+		{
+			name:    "ipv6 address with tcp4",
+			input:   "PROXY TCP4 2001:db8::1 198.51.100.1 56324 443\r\n",
+			wantErr: errProxyProtocolHeaderInvalid,
+		},
+		{
+			name:    "port with leading zero",
+			input:   "PROXY TCP4 192.0.2.1 198.51.100.1 056324 443\r\n",
+			wantErr: errProxyProtocolHeaderInvalid,
+		},
+		{
+			name:    "missing fields",
+			input:   "PROXY TCP4 192.0.2.1 198.51.100.1 56324\r\n",
+			wantErr: errProxyProtocolHeaderInvalid,
+		},
+		{
+			name:    "too long",
+			input:   "PROXY " + strings.Repeat("A", proxyProtocolV1MaxLength) + "\r\n",
+			wantErr: errProxyProtocolHeaderInvalid,
+		},
+		{
+			name:    "no signature",
+			input:   "GET / HTTP/1.1\r\n\r\n",
+			wantErr: errProxyProtocolHeaderInvalid,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := bufio.NewReader(strings.NewReader(tc.input + "payload"))
+
+			header, err := readProxyHeader(reader)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Errorf("expected %v, got %v", tc.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if header.Version != 1 || header.Command != ProxyCommandProxy {
+				t.Errorf("unexpected header: %+v", header)
+			}
+
+			assertAddr(t, header.SourceAddr, tc.wantSource)
+			assertAddr(t, header.DestinationAddr, tc.wantDestination)
+
+			if rest, _ := io.ReadAll(reader); string(rest) != "payload" {
+				t.Errorf("expected the payload to be left unread, got %q", rest)
+			}
+		})
+	}
+}
+
+func TestReadProxyHeaderV2(t *testing.T) {
+	t.Run("tcp4 with tlvs", func(t *testing.T) {
+		addresses := []byte{192, 0, 2, 1, 198, 51, 100, 1, 0xDC, 0x04, 0x01, 0xBB}
+		tlvs := append(
+			encodeTestTLV(ProxyTLVTypeAuthority, []byte("example.com")),
+			encodeTestTLV(ProxyTLVTypeAWS, []byte("\x01vpce-0123456789"))...,
+		)
+		input := encodeTestProxyHeaderV2(0x21, 0x11, append(addresses, tlvs...))
+		reader := bufio.NewReader(bytes.NewReader(append(input, "payload"...)))
+
+		header, err := readProxyHeader(reader)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if header.Version != 2 || header.Command != ProxyCommandProxy {
+			t.Errorf("unexpected header: %+v", header)
+		}
+
+		assertAddr(t, header.SourceAddr, "192.0.2.1:56324")
+		assertAddr(t, header.DestinationAddr, "198.51.100.1:443")
+
+		if len(header.TLVs) != 2 {
+			t.Fatalf("expected 2 TLVs, got %d", len(header.TLVs))
+		}
+
+		if value, ok := header.TLV(ProxyTLVTypeAuthority); !ok || string(value) != "example.com" {
+			t.Errorf("unexpected authority: %q", value)
+		}
+
+		if _, ok := header.TLV(ProxyTLVTypeUniqueID); ok {
+			t.Error("expected no unique ID")
+		}
+
+		if rest, _ := io.ReadAll(reader); string(rest) != "payload" {
+			t.Errorf("expected the payload to be left unread, got %q", rest)
+		}
+	})
+
+	t.Run("tcp6", func(t *testing.T) {
+		addresses := make([]byte, proxyProtocolV2Inet6Length)
+		copy(addresses, net.ParseIP("2001:db8::1"))
+		copy(addresses[16:], net.ParseIP("2001:db8::2"))
+		binary.BigEndian.PutUint16(addresses[32:], 56324)
+		binary.BigEndian.PutUint16(addresses[34:], 443)
+
+		header, err := readProxyHeader(bufio.NewReader(bytes.NewReader(encodeTestProxyHeaderV2(0x21, 0x21, addresses))))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		assertAddr(t, header.SourceAddr, "[2001:db8::1]:56324")
+		assertAddr(t, header.DestinationAddr, "[2001:db8::2]:443")
+	})
+
+	t.Run("unix", func(t *testing.T) {
+		addresses := make([]byte, proxyProtocolV2UnixLength)
+		copy(addresses, "/run/client.sock")
+		copy(addresses[108:], "/run/server.sock")
+
+		header, err := readProxyHeader(bufio.NewReader(bytes.NewReader(encodeTestProxyHeaderV2(0x21, 0x31, addresses))))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		assertAddr(t, header.SourceAddr, "/run/client.sock")
+		assertAddr(t, header.DestinationAddr, "/run/server.sock")
+	})
+
+	t.Run("local command ignores addresses", func(t *testing.T) {
+		addresses := []byte{192, 0, 2, 1, 198, 51, 100, 1, 0xDC, 0x04, 0x01, 0xBB}
+
+		header, err := readProxyHeader(bufio.NewReader(bytes.NewReader(encodeTestProxyHeaderV2(0x20, 0x11, addresses))))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if header.Command != ProxyCommandLocal || header.SourceAddr != nil {
+			t.Errorf("unexpected header: %+v", header)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			input   []byte
+			wantErr error
+		}{
+			{
+				name:    "unsupported version",
+				input:   encodeTestProxyHeaderV2(0x11, 0x11, make([]byte, 12)),
+				wantErr: errProxyProtocolVersionInvalid,
+			},
+			{
+				name:    "unknown command",
+				input:   encodeTestProxyHeaderV2(0x22, 0x11, make([]byte, 12)),
+				wantErr: errProxyProtocolHeaderInvalid,
+			},
+			{
+				name:    "short address block",
+				input:   encodeTestProxyHeaderV2(0x21, 0x11, make([]byte, 8)),
+				wantErr: errProxyProtocolHeaderInvalid,
+			},
+			{
+				name:    "truncated payload",
+				input:   encodeTestProxyHeaderV2(0x21, 0x11, make([]byte, 12))[:20],
+				wantErr: errProxyProtocolHeaderInvalid,
+			},
+			{
+				name:    "truncated tlv",
+				input:   encodeTestProxyHeaderV2(0x21, 0x11, append(make([]byte, 12), 0x01, 0x00, 0x05, 'a')),
+				wantErr: errProxyProtocolTLVInvalid,
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := readProxyHeader(bufio.NewReader(bytes.NewReader(tc.input)))
+				if !errors.Is(err, tc.wantErr) {
+					t.Errorf("expected %v, got %v", tc.wantErr, err)
+				}
+			})
+		}
+	})
+}
+
+func encodeTestProxyHeaderV2(versionCommand byte, familyTransport byte, payload []byte) []byte {
+	result := append([]byte{}, proxyProtocolV2Signature...)
+	result = append(result, versionCommand, familyTransport)
+	result = binary.BigEndian.AppendUint16(result, uint16(len(payload)))
+
+	return append(result, payload...)
+}
+
+func encodeTestTLV(tlvType ProxyTLVType, value []byte) []byte {
+	result := []byte{byte(tlvType)}
+	result = binary.BigEndian.AppendUint16(result, uint16(len(value)))
+
+	return append(result, value...)
+}
+
+func assertAddr(t *testing.T, addr net.Addr, expected string) {
+	t.Helper()
+
+	if expected == "" {
+		if addr != nil {
+			t.Errorf("expected nil address, got %s", addr)
+		}
+
+		return
+	}
+
+	if addr == nil || addr.String() != expected {
+		t.Errorf("expected address %s, got %v", expected, addr)
+	}
+}
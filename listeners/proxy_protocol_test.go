@@ -0,0 +1,182 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listeners
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/relychan/gohttps/middlewares"
+	"github.com/relychan/goutils"
+)
+
+func TestProxyProtocolConfig_Validate(t *testing.T) {
+	if err := (ProxyProtocolConfig{TrustedIPPrefixes: []string{"10.0.0.0/8", "2001:db8::/32"}}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := ProxyProtocolConfig{TrustedIPPrefixes: []string{"10.0.0.1"}}.Validate()
+	if !errors.Is(err, errProxyProtocolTrustedIPPrefixInvalid) {
+		t.Errorf("expected errProxyProtocolTrustedIPPrefixInvalid, got %v", err)
+	}
+}
+
+func TestProxyProtocolListener(t *testing.T) {
+	t.Run("v1", func(t *testing.T) {
+		baseURL := startTestProxyProtocolServer(t, ProxyProtocolConfig{
+			TrustedIPPrefixes: []string{"127.0.0.0/8"},
+		})
+
+		body := sendTestRequest(t, baseURL, []byte("PROXY TCP4 192.0.2.1 198.51.100.1 56324 443\r\n"))
+		if body != "192.0.2.1 " {
+			t.Errorf("unexpected response: %s", body)
+		}
+	})
+
+	t.Run("v2", func(t *testing.T) {
+		baseURL := startTestProxyProtocolServer(t, ProxyProtocolConfig{
+			TrustedIPPrefixes: []string{"127.0.0.0/8"},
+		})
+
+		payload := append(
+			[]byte{192, 0, 2, 1, 198, 51, 100, 1, 0xDC, 0x04, 0x01, 0xBB},
+			encodeTestTLV(ProxyTLVTypeAuthority, []byte("example.com"))...,
+		)
+
+		body := sendTestRequest(t, baseURL, encodeTestProxyHeaderV2(0x21, 0x11, payload))
+		if body != "192.0.2.1 example.com" {
+			t.Errorf("unexpected response: %s", body)
+		}
+	})
+
+	t.Run("serves untrusted sources as is", func(t *testing.T) {
+		baseURL := startTestProxyProtocolServer(t, ProxyProtocolConfig{
+			TrustedIPPrefixes: []string{"192.0.2.0/24"},
+		})
+
+		body := sendTestRequest(t, baseURL, nil)
+		if body != "127.0.0.1 " {
+			t.Errorf("unexpected response: %s", body)
+		}
+	})
+
+	t.Run("closes connections with invalid headers", func(t *testing.T) {
+		baseURL := startTestProxyProtocolServer(t, ProxyProtocolConfig{})
+
+		conn, err := net.Dial("tcp", baseURL)
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer conn.Close()
+
+		_, _ = conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+		if response, _ := io.ReadAll(conn); len(response) > 0 {
+			t.Errorf("expected the connection to be closed, got %s", response)
+		}
+	})
+
+	t.Run("header timeout", func(t *testing.T) {
+		baseURL := startTestProxyProtocolServer(t, ProxyProtocolConfig{
+			HeaderTimeout: goutils.Duration(50 * time.Millisecond),
+		})
+
+		conn, err := net.Dial("tcp", baseURL)
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer conn.Close()
+
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+		startTime := time.Now()
+		_, _ = io.ReadAll(conn)
+
+		if elapsed := time.Since(startTime); elapsed > 2*time.Second {
+			t.Errorf("expected the connection to be closed after the header timeout, took %s", elapsed)
+		}
+	})
+}
+
+// startTestProxyProtocolServer starts a server that responds with the client IP and the authority TLV.
+func startTestProxyProtocolServer(t *testing.T, config ProxyProtocolConfig) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	ppl, err := NewProxyProtocolListener(listener, config)
+	if err != nil {
+		t.Fatalf("failed to create listener: %v", err)
+	}
+
+	handler := middlewares.ClientIP(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var authority string
+
+		if header, ok := ProxyHeaderFromContext(r.Context()); ok {
+			value, _ := header.TLV(ProxyTLVTypeAuthority)
+			authority = string(value)
+		}
+
+		_, _ = w.Write([]byte(middleware.GetClientIP(r.Context()) + " " + authority))
+	}))
+
+	server := &http.Server{
+		Handler:     handler,
+		ConnContext: WithProxyHeaderContext,
+	}
+
+	go func() {
+		_ = server.Serve(ppl)
+	}()
+
+	t.Cleanup(func() {
+		_ = server.Shutdown(context.Background())
+	})
+
+	return listener.Addr().String()
+}
+
+func sendTestRequest(t *testing.T, address string, header []byte) string {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	_, _ = conn.Write(append(header, "GET / HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n"...))
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	response, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+
+	_, body, _ := strings.Cut(string(response), "\r\n\r\n")
+
+	return body
+}
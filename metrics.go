@@ -0,0 +1,116 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"go.opentelemetry.io/otel/metric"
+)
+
+const instrumentationName = "github.com/relychan/gohttps"
+
+// serverMetrics holds OpenTelemetry instruments of the server.
+type serverMetrics struct {
+	meter               metric.Meter
+	certificateReloads  metric.Int64Counter
+	certificateNotAfter metric.Int64ObservableGauge
+	activeConnections   metric.Int64ObservableGauge
+	rejectedConnections metric.Int64Counter
+	openConnections     metric.Int64ObservableGauge
+	connectionDuration  metric.Float64Histogram
+	connectionRequests  metric.Int64Histogram
+}
+
+func newServerMetrics(meterProvider metric.MeterProvider) (*serverMetrics, error) {
+	meter := meterProvider.Meter(instrumentationName)
+
+	certificateReloads, err := meter.Int64Counter(
+		"gohttps.tls.certificate.reloads",
+		metric.WithDescription("Number of TLS certificate reloads."),
+		metric.WithUnit("{reload}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	certificateNotAfter, err := meter.Int64ObservableGauge(
+		"gohttps.tls.certificate.not_after",
+		metric.WithDescription("The expiry time of TLS certificates that the server serves, in Unix seconds."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	activeConnections, err := meter.Int64ObservableGauge(
+		"gohttps.connection_limit.active",
+		metric.WithDescription("Number of open connections that count towards the connection limits."),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rejectedConnections, err := meter.Int64Counter(
+		"gohttps.connection_limit.rejected",
+		metric.WithDescription("Number of connections that are rejected by the connection limits."),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	openConnections, err := meter.Int64ObservableGauge(
+		"gohttps.connection.open",
+		metric.WithDescription(
+			"Number of open connections by state: new, active or idle. "+
+				"Hijacked connections, e.g. WebSockets, are excluded because the server no longer tracks them.",
+		),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	connectionDuration, err := meter.Float64Histogram(
+		"gohttps.connection.duration",
+		metric.WithDescription("The lifetime of connections until they are closed or hijacked, by the final state: closed or hijacked."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.1, 0.5, 1, 5, 10, 30, 60, 300, 600, 1800, 3600),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	connectionRequests, err := meter.Int64Histogram(
+		"gohttps.connection.requests",
+		metric.WithDescription("Number of requests served on connections until they are closed or hijacked, by the final state: closed or hijacked."),
+		metric.WithUnit("{request}"),
+		metric.WithExplicitBucketBoundaries(0, 1, 2, 5, 10, 25, 50, 100, 250, 500, 1000),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &serverMetrics{
+		meter:               meter,
+		certificateReloads:  certificateReloads,
+		certificateNotAfter: certificateNotAfter,
+		activeConnections:   activeConnections,
+		rejectedConnections: rejectedConnections,
+		openConnections:     openConnections,
+		connectionDuration:  connectionDuration,
+		connectionRequests:  connectionRequests,
+	}, nil
+}
@@ -0,0 +1,295 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/relychan/goutils"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var errAccessLogInvalid = errors.New("invalid access log config")
+
+// AccessLogField represents a field of access logs.
+type AccessLogField string
+
+const (
+	// AccessLogFieldMethod logs the HTTP method of the request.
+	AccessLogFieldMethod AccessLogField = "method"
+	// AccessLogFieldRoute logs the matched route pattern of chi, e.g. /users/{id}.
+	AccessLogFieldRoute AccessLogField = "route"
+	// AccessLogFieldPath logs the URL path of the request.
+	AccessLogFieldPath AccessLogField = "path"
+	// AccessLogFieldStatus logs the status code of the response.
+	AccessLogFieldStatus AccessLogField = "status"
+	// AccessLogFieldLatency logs the duration to serve the request.
+	AccessLogFieldLatency AccessLogField = "latency"
+	// AccessLogFieldBytesIn logs the number of bytes read from the request body.
+	AccessLogFieldBytesIn AccessLogField = "bytes_in"
+	// AccessLogFieldBytesOut logs the number of bytes written to the response body.
+	AccessLogFieldBytesOut AccessLogField = "bytes_out"
+	// AccessLogFieldClientIP logs the client IP that the ClientIP middleware resolves, or the host of the remote address.
+	AccessLogFieldClientIP AccessLogField = "client_ip"
+	// AccessLogFieldUserAgent logs the User-Agent header of the request.
+	AccessLogFieldUserAgent AccessLogField = "user_agent"
+	// AccessLogFieldRequestID logs the ID of the request.
+	AccessLogFieldRequestID AccessLogField = "request_id"
+	// AccessLogFieldTraceID logs the trace ID of the request if the request is traced.
+	AccessLogFieldTraceID AccessLogField = "trace_id"
+)
+
+var accessLogFields = []AccessLogField{
+	AccessLogFieldMethod,
+	AccessLogFieldRoute,
+	AccessLogFieldPath,
+	AccessLogFieldStatus,
+	AccessLogFieldLatency,
+	AccessLogFieldBytesIn,
+	AccessLogFieldBytesOut,
+	AccessLogFieldClientIP,
+	AccessLogFieldUserAgent,
+	AccessLogFieldRequestID,
+	AccessLogFieldTraceID,
+}
+
+var accessLogStatusClasses = []string{"1xx", "2xx", "3xx", "4xx", "5xx"}
+
+// AccessLogConfig represents the configuration of the structured access logs of HTTP requests.
+type AccessLogConfig struct {
+	// Log a line for every served HTTP request.
+	Enabled bool `env:"SERVER_ACCESS_LOG_ENABLED" json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// The fields of access logs. Default is all fields.
+	Fields []AccessLogField `env:"SERVER_ACCESS_LOG_FIELDS" json:"fields,omitempty" yaml:"fields,omitempty" jsonschema:"enum=method,enum=route,enum=path,enum=status,enum=latency,enum=bytes_in,enum=bytes_out,enum=client_ip,enum=user_agent,enum=request_id,enum=trace_id"`
+	// The ratios of requests that are logged by the class of the status code, e.g. 1 for 5xx and 0.01 for 2xx.
+	// Keys are 1xx, 2xx, 3xx, 4xx or 5xx. Requests of classes that are not set are always logged.
+	SampleRatios map[string]float64 `json:"sampleRatios,omitempty" yaml:"sampleRatios,omitempty"`
+	// Requests that take longer than the threshold are always logged at the WARN level. Disabled if zero.
+	SlowRequestThreshold goutils.Duration `env:"SERVER_ACCESS_LOG_SLOW_REQUEST_THRESHOLD" json:"slowRequestThreshold,omitempty" yaml:"slowRequestThreshold,omitempty"`
+	// List of URL paths that are not logged, e.g. /metrics or /healthz.
+	// If not set, the server excludes the metrics and health check endpoints.
+	ExcludedPaths []string `env:"SERVER_ACCESS_LOG_EXCLUDED_PATHS" json:"excludedPaths,omitempty" yaml:"excludedPaths,omitempty"`
+}
+
+// IsEnabled checks if the access log is enabled.
+func (alc *AccessLogConfig) IsEnabled() bool {
+	return alc != nil && alc.Enabled
+}
+
+// Validate checks if the configuration is valid.
+func (alc AccessLogConfig) Validate() error {
+	for _, field := range alc.Fields {
+		if !slices.Contains(accessLogFields, field) {
+			return fmt.Errorf("%w: unknown field %s", errAccessLogInvalid, field)
+		}
+	}
+
+	for class, ratio := range alc.SampleRatios {
+		if !slices.Contains(accessLogStatusClasses, class) {
+			return fmt.Errorf("%w: unknown status class %s of sampleRatios", errAccessLogInvalid, class)
+		}
+
+		if ratio < 0 || ratio > 1 {
+			return fmt.Errorf("%w: sample ratio of %s must be between 0 and 1", errAccessLogInvalid, class)
+		}
+	}
+
+	if alc.SlowRequestThreshold < 0 {
+		return fmt.Errorf("%w: slowRequestThreshold must not be negative", errAccessLogInvalid)
+	}
+
+	return nil
+}
+
+type accessLog struct {
+	logger        *slog.Logger
+	fields        []AccessLogField
+	sampleRatios  map[string]float64
+	slowThreshold time.Duration
+	excludedPaths []string
+}
+
+// AccessLog creates a middleware that logs served HTTP requests with the structured logger.
+// The ClientIP and tracing middlewares must run before this middleware to log the client IP and trace ID.
+func AccessLog(config AccessLogConfig, logger *slog.Logger) func(http.Handler) http.Handler {
+	al := &accessLog{
+		logger:        logger,
+		fields:        config.Fields,
+		sampleRatios:  config.SampleRatios,
+		slowThreshold: time.Duration(config.SlowRequestThreshold),
+		excludedPaths: config.ExcludedPaths,
+	}
+
+	if al.logger == nil {
+		al.logger = slog.Default()
+	}
+
+	if len(al.fields) == 0 {
+		al.fields = accessLogFields
+	}
+
+	return al.handle
+}
+
+func (al *accessLog) handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if slices.Contains(al.excludedPaths, r.URL.Path) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		startTime := time.Now()
+
+		r, requestID := storeRequestID(r)
+
+		var body *countingReader
+
+		if r.Body != nil && r.Body != http.NoBody {
+			body = &countingReader{ReadCloser: r.Body}
+			r.Body = body
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		// Requests that panic are logged as internal errors before the panic is recovered upstream.
+		defer func() {
+			recovered := recover()
+
+			statusCode := ww.Status()
+
+			switch {
+			case recovered != nil:
+				statusCode = http.StatusInternalServerError
+			case statusCode == 0:
+				statusCode = http.StatusOK
+			}
+
+			al.log(r, ww, body, requestID, statusCode, time.Since(startTime))
+
+			if recovered != nil {
+				panic(recovered)
+			}
+		}()
+
+		next.ServeHTTP(ww, r)
+	})
+}
+
+// log writes the access log entry of the request if it is sampled or slow.
+func (al *accessLog) log(
+	r *http.Request,
+	ww middleware.WrapResponseWriter,
+	body *countingReader,
+	requestID string,
+	statusCode int,
+	latency time.Duration,
+) {
+	level := slog.LevelInfo
+
+	if al.slowThreshold > 0 && latency >= al.slowThreshold {
+		// Slow requests are always logged.
+		level = slog.LevelWarn
+	} else if !al.shouldSample(statusCode) {
+		return
+	}
+
+	ctx := r.Context()
+
+	if !al.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, len(al.fields))
+
+	for _, field := range al.fields {
+		switch field {
+		case AccessLogFieldMethod:
+			attrs = append(attrs, slog.String(string(field), r.Method))
+		case AccessLogFieldRoute:
+			attrs = append(attrs, slog.String(string(field), routePattern(r)))
+		case AccessLogFieldPath:
+			attrs = append(attrs, slog.String(string(field), r.URL.Path))
+		case AccessLogFieldStatus:
+			attrs = append(attrs, slog.Int(string(field), statusCode))
+		case AccessLogFieldLatency:
+			attrs = append(attrs, slog.Duration(string(field), latency))
+		case AccessLogFieldBytesIn:
+			var bytesIn int64
+
+			if body != nil {
+				bytesIn = body.bytes
+			}
+
+			attrs = append(attrs, slog.Int64(string(field), bytesIn))
+		case AccessLogFieldBytesOut:
+			attrs = append(attrs, slog.Int(string(field), ww.BytesWritten()))
+		case AccessLogFieldClientIP:
+			attrs = append(attrs, slog.String(string(field), clientIP(r)))
+		case AccessLogFieldUserAgent:
+			attrs = append(attrs, slog.String(string(field), r.UserAgent()))
+		case AccessLogFieldRequestID:
+			attrs = append(attrs, slog.String(string(field), requestID))
+		case AccessLogFieldTraceID:
+			if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsSampled() {
+				attrs = append(attrs, slog.String(string(field), spanContext.TraceID().String()))
+			}
+		}
+	}
+
+	al.logger.LogAttrs(context.WithoutCancel(ctx), level, "http request", attrs...)
+}
+
+func (al *accessLog) shouldSample(statusCode int) bool {
+	ratio, ok := al.sampleRatios[statusClass(statusCode)]
+
+	switch {
+	case !ok || ratio >= 1:
+		return true
+	case ratio <= 0:
+		return false
+	default:
+		return rand.Float64() < ratio //nolint:gosec
+	}
+}
+
+// statusClass returns the class of the status code, e.g. 2xx.
+func statusClass(statusCode int) string {
+	return strconv.Itoa(statusCode/100) + "xx"
+}
+
+// clientIP returns the client IP that the ClientIP middleware resolves, or the host of the remote address.
+func clientIP(r *http.Request) string {
+	if ip := middleware.GetClientIP(r.Context()); ip != "" {
+		return ip
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
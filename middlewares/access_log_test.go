@@ -0,0 +1,253 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/relychan/gohttps/httputils"
+	"github.com/relychan/goutils"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newAccessLogTestRouter(config AccessLogConfig, buf *bytes.Buffer) *chi.Mux {
+	router := chi.NewRouter()
+	router.Use(AccessLog(config, slog.New(slog.NewJSONHandler(buf, nil))))
+	router.Post("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		body := make([]byte, 64)
+		n, _ := r.Body.Read(body)
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body[:n])
+	})
+	router.Get("/error", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	router.Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	})
+	router.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {})
+
+	return router
+}
+
+func readAccessLogs(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var results []map[string]any
+
+	for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var result map[string]any
+
+		if err := json.Unmarshal([]byte(line), &result); err != nil {
+			t.Fatalf("failed to decode the log line: %v", err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func TestAccessLog(t *testing.T) {
+	t.Run("default fields", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		router := newAccessLogTestRouter(AccessLogConfig{Enabled: true}, &buf)
+
+		traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+		spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+		ctx := trace.ContextWithSpanContext(t.Context(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+		}))
+
+		req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/users/42", strings.NewReader("hello"))
+		req.RemoteAddr = "203.0.113.10:1234"
+		req.Header.Set("User-Agent", "test")
+		req.Header.Set("X-Request-Id", "request-1")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		logs := readAccessLogs(t, &buf)
+		if len(logs) != 1 {
+			t.Fatalf("expected 1 log line, got %d", len(logs))
+		}
+
+		for key, expected := range map[string]any{
+			"level":      "INFO",
+			"msg":        "http request",
+			"method":     "POST",
+			"route":      "/users/{id}",
+			"path":       "/users/42",
+			"status":     float64(http.StatusCreated),
+			"bytes_in":   float64(5),
+			"bytes_out":  float64(5),
+			"client_ip":  "203.0.113.10",
+			"user_agent": "test",
+			"request_id": "request-1",
+			"trace_id":   traceID.String(),
+		} {
+			if logs[0][key] != expected {
+				t.Errorf("expected %s to be %v, got %v", key, expected, logs[0][key])
+			}
+		}
+
+		if _, ok := logs[0]["latency"]; !ok {
+			t.Error("expected the latency to be logged")
+		}
+	})
+
+	t.Run("selected fields", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		router := newAccessLogTestRouter(AccessLogConfig{
+			Enabled: true,
+			Fields:  []AccessLogField{AccessLogFieldRoute, AccessLogFieldStatus},
+		}, &buf)
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/error", nil))
+
+		logs := readAccessLogs(t, &buf)
+		if len(logs) != 1 {
+			t.Fatalf("expected 1 log line, got %d", len(logs))
+		}
+
+		if _, ok := logs[0]["method"]; ok {
+			t.Error("expected the method not to be logged")
+		}
+
+		if logs[0]["route"] != "/error" || logs[0]["status"] != float64(http.StatusInternalServerError) {
+			t.Errorf("unexpected log line: %v", logs[0])
+		}
+	})
+
+	t.Run("sampling and slow requests", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		router := newAccessLogTestRouter(AccessLogConfig{
+			Enabled:              true,
+			Fields:               []AccessLogField{AccessLogFieldRoute},
+			SampleRatios:         map[string]float64{"2xx": 0},
+			SlowRequestThreshold: goutils.Duration(10 * time.Millisecond),
+			ExcludedPaths:        []string{"/healthz"},
+		}, &buf)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users/1", strings.NewReader("a")))
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/error", nil))
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		logs := readAccessLogs(t, &buf)
+		if len(logs) != 2 {
+			t.Fatalf("expected 2 log lines, got %d", len(logs))
+		}
+
+		if logs[0]["route"] != "/error" || logs[0]["level"] != "INFO" {
+			t.Errorf("expected the server error to be logged, got %v", logs[0])
+		}
+
+		if logs[1]["route"] != "/slow" || logs[1]["level"] != "WARN" {
+			t.Errorf("expected the slow request to be logged at WARN, got %v", logs[1])
+		}
+	})
+}
+
+func TestAccessLogPanic(t *testing.T) {
+	var buf bytes.Buffer
+
+	router := chi.NewRouter()
+	router.Use(middleware.Recoverer)
+	router.Use(AccessLog(AccessLogConfig{}, slog.New(slog.NewJSONHandler(&buf, nil))))
+	router.Get("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected the panic to be recovered with status 500, got %d", w.Code)
+	}
+
+	logs := readAccessLogs(t, &buf)
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 log line, got %d", len(logs))
+	}
+
+	if logs[0]["status"] != float64(http.StatusInternalServerError) || logs[0]["route"] != "/panic" {
+		t.Errorf("expected the panicked request to be logged with status 500, got %v", logs[0])
+	}
+}
+
+func TestAccessLogRequestID(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	// The RequestID middleware is disabled and the request has no ID header.
+	router := chi.NewRouter()
+	router.Use(RequestLogger(logger))
+	router.Use(AccessLog(AccessLogConfig{}, logger))
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+		httputils.GetRequestLogger(r).Info("hello")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	logs := readAccessLogs(t, &buf)
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 log lines, got %d", len(logs))
+	}
+
+	if logs[0]["request_id"] == nil || logs[0]["request_id"] != logs[1]["request_id"] {
+		t.Errorf("expected the same request ID in all log lines, got %v and %v", logs[0]["request_id"], logs[1]["request_id"])
+	}
+}
+
+func TestAccessLogConfig_Validate(t *testing.T) {
+	valid := AccessLogConfig{
+		Fields:       []AccessLogField{AccessLogFieldMethod},
+		SampleRatios: map[string]float64{"2xx": 0.01, "5xx": 1},
+	}
+
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, config := range []AccessLogConfig{
+		{Fields: []AccessLogField{"unknown"}},
+		{SampleRatios: map[string]float64{"200": 1}},
+		{SampleRatios: map[string]float64{"2xx": 2}},
+		{SlowRequestThreshold: goutils.Duration(-time.Second)},
+	} {
+		if err := config.Validate(); !errors.Is(err, errAccessLogInvalid) {
+			t.Errorf("expected errAccessLogInvalid, got %v", err)
+		}
+	}
+}
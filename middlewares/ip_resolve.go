@@ -41,6 +41,7 @@ const (
 	// ClientIPFromRemoteAddr stores the client IP read from the TCP RemoteAddr of the incoming request — the IP address of whoever opened the connection to this server.
 	// Use this strategy when this server is directly connected to the public internet with NO reverse proxy in front of it.
 	// Behind a reverse proxy, RemoteAddr is the proxy's IP, not the client's — use ClientIPFromHeader or ClientIPFromXFF instead.
+	// Behind a TCP load balancer that sends the PROXY protocol header, enable the proxyProtocol config so RemoteAddr is rewritten to the client's IP.
 	// IPv4 clients on a dual-stack listener surface as ::ffff:a.b.c.d; they fold to plain v4 before storage so one logical client maps to one key.
 	// IPv6 zones are preserved (link-local connections may legitimately have one).
 	ClientIPFromRemoteAddr ClientIPResolutionType = "remote_addr"
@@ -0,0 +1,275 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/relychan/gohttps/httputils"
+	"github.com/relychan/goutils"
+)
+
+const (
+	defaultMinDataRateGracePeriod = 5 * time.Second
+	// The size of the write buffer of a net/http connection. Responses are flushed once the buffered bytes exceed it.
+	minDataRateFlushSize = 4 << 10
+)
+
+var (
+	// ErrMinDataRate occurs when a client transfers data slower than the minimum data rate.
+	ErrMinDataRate = errors.New("data rate is below the minimum")
+
+	errMinDataRateInvalid = errors.New("invalid minimum data rate")
+)
+
+// MinDataRateConfig represents the minimum rates that clients must transfer request bodies and responses with.
+// The rates are only enforced while the handler reads the request body or writes the response,
+// so slow handlers are not penalized. The connection is closed if the rate is below the minimum after the grace period.
+// Response writes are flushed once more than 4 KiB is buffered, so the rate applies to the bytes that the client receives
+// while small responses keep their Content-Length. The last buffered bytes are sent within the write timeout.
+type MinDataRateConfig struct {
+	// The minimum rate in bytes per second to read request bodies. Disabled if zero.
+	RequestBodyBytesPerSecond int64 `env:"SERVER_MIN_REQUEST_BODY_DATA_RATE" json:"requestBodyBytesPerSecond,omitempty" yaml:"requestBodyBytesPerSecond,omitempty" jsonschema:"minimum=0"`
+	// The minimum rate in bytes per second to write responses. Disabled if zero.
+	ResponseBytesPerSecond int64 `env:"SERVER_MIN_RESPONSE_DATA_RATE" json:"responseBytesPerSecond,omitempty" yaml:"responseBytesPerSecond,omitempty" jsonschema:"minimum=0"`
+	// The duration that a transfer may stay below the minimum rate when it starts. Default is 5 seconds.
+	GracePeriod goutils.Duration `env:"SERVER_MIN_DATA_RATE_GRACE_PERIOD" json:"gracePeriod,omitempty" yaml:"gracePeriod,omitempty"`
+}
+
+// Validate checks if the configuration is valid.
+func (mdrc MinDataRateConfig) Validate() error {
+	switch {
+	case mdrc.RequestBodyBytesPerSecond < 0:
+		return fmt.Errorf("%w: requestBodyBytesPerSecond must not be negative", errMinDataRateInvalid)
+	case mdrc.ResponseBytesPerSecond < 0:
+		return fmt.Errorf("%w: responseBytesPerSecond must not be negative", errMinDataRateInvalid)
+	case mdrc.GracePeriod < 0:
+		return fmt.Errorf("%w: gracePeriod must not be negative", errMinDataRateInvalid)
+	}
+
+	return nil
+}
+
+// GetGracePeriod returns the duration that a transfer may stay below the minimum rate. Default is 5 seconds.
+func (mdrc MinDataRateConfig) GetGracePeriod() time.Duration {
+	if mdrc.GracePeriod > 0 {
+		return time.Duration(mdrc.GracePeriod)
+	}
+
+	return defaultMinDataRateGracePeriod
+}
+
+// MinDataRate creates a middleware that enforces the minimum data rates with read and write deadlines of the connection.
+// Deadlines never extend beyond the read and write timeouts of the server. Zero timeouts mean no limit.
+// Requests are served as is if the connection does not support deadlines.
+func MinDataRate(config MinDataRateConfig, readTimeout, writeTimeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if config.RequestBodyBytesPerSecond <= 0 && config.ResponseBytesPerSecond <= 0 {
+			return next
+		}
+
+		gracePeriod := config.GetGracePeriod()
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+			controller := http.NewResponseController(w)
+
+			if config.RequestBodyBytesPerSecond > 0 && r.Body != nil && r.Body != http.NoBody {
+				readLimit := deadlineAfter(now, readTimeout)
+
+				err := controller.SetReadDeadline(readLimit)
+				if err == nil {
+					r.Body = &minDataRateReader{
+						ReadCloser: r.Body,
+						rate: minDataRate{
+							name:        "request body",
+							request:     r,
+							bytesPerSec: config.RequestBodyBytesPerSecond,
+							gracePeriod: gracePeriod,
+							limit:       readLimit,
+							setDeadline: controller.SetReadDeadline,
+							header:      w.Header,
+						},
+					}
+				}
+			}
+
+			if config.ResponseBytesPerSecond > 0 {
+				writeLimit := deadlineAfter(now, writeTimeout)
+
+				err := controller.SetWriteDeadline(writeLimit)
+				if err == nil {
+					w = &minDataRateWriter{
+						ResponseWriter: w,
+						controller:     controller,
+						rate: minDataRate{
+							name:        "response",
+							request:     r,
+							bytesPerSec: config.ResponseBytesPerSecond,
+							gracePeriod: gracePeriod,
+							limit:       writeLimit,
+							setDeadline: controller.SetWriteDeadline,
+						},
+					}
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// minDataRate tracks the bytes and the duration of a transfer to compute deadlines of the minimum rate.
+type minDataRate struct {
+	name        string
+	request     *http.Request
+	bytesPerSec int64
+	gracePeriod time.Duration
+	// The deadline of the server timeout. Zero means no deadline.
+	limit       time.Time
+	setDeadline func(deadline time.Time) error
+	// Returns the response header to close the connection once the rate is violated. Nil for responses.
+	header func() http.Header
+
+	bytes   int64
+	elapsed time.Duration
+}
+
+// transfer runs the read or write with the deadline that the minimum rate allows for the expected bytes.
+func (mdr *minDataRate) transfer(expectedBytes int, fn func() (int, error)) (int, error) {
+	start := time.Now()
+	allowed := max(
+		mdr.gracePeriod,
+		time.Duration(float64(mdr.bytes+int64(expectedBytes))/float64(mdr.bytesPerSec)*float64(time.Second)),
+	)
+
+	deadline := start.Add(allowed - mdr.elapsed)
+	capped := !mdr.limit.IsZero() && deadline.After(mdr.limit)
+
+	if capped {
+		deadline = mdr.limit
+	}
+
+	_ = mdr.setDeadline(deadline)
+
+	n, err := fn()
+
+	mdr.bytes += int64(n)
+	mdr.elapsed += time.Since(start)
+
+	// Reset the deadline so the server is not affected while the handler does not transfer data.
+	_ = mdr.setDeadline(mdr.limit)
+
+	if err == nil || capped || !errors.Is(err, os.ErrDeadlineExceeded) {
+		return n, err
+	}
+
+	httputils.GetRequestLogger(mdr.request).Warn(
+		"closing the connection of a slow client",
+		slog.String("reason", mdr.name+" data rate is below the minimum"),
+		slog.String("remote_addr", mdr.request.RemoteAddr),
+		slog.Int64("bytes", mdr.bytes),
+		slog.Duration("elapsed", mdr.elapsed),
+		slog.Int64("min_bytes_per_second", mdr.bytesPerSec),
+	)
+
+	if mdr.header != nil {
+		mdr.header().Set("Connection", "close")
+	}
+
+	return n, fmt.Errorf("%w: %s: %w", ErrMinDataRate, mdr.name, err)
+}
+
+// minDataRateReader enforces the minimum data rate on reads of the request body.
+type minDataRateReader struct {
+	io.ReadCloser
+
+	rate minDataRate
+}
+
+// Read reads the request body within the deadline of the minimum data rate.
+func (mdrr *minDataRateReader) Read(p []byte) (int, error) {
+	// Expect at least one byte so a stalled client fails once the allowance is spent.
+	return mdrr.rate.transfer(1, func() (int, error) {
+		return mdrr.ReadCloser.Read(p)
+	})
+}
+
+// minDataRateWriter enforces the minimum data rate on writes of the response.
+type minDataRateWriter struct {
+	http.ResponseWriter
+
+	controller *http.ResponseController
+	rate       minDataRate
+	// The number of bytes that are written since the last flush.
+	buffered int
+}
+
+// Write writes the data within the deadline of the minimum data rate. The data is flushed once the buffer is full.
+// Otherwise, the data would only fill the buffer of the server before the deadline is reset.
+func (mdrw *minDataRateWriter) Write(p []byte) (int, error) {
+	return mdrw.rate.transfer(len(p), func() (int, error) {
+		n, err := mdrw.ResponseWriter.Write(p)
+		if err != nil {
+			return n, err
+		}
+
+		mdrw.buffered += n
+
+		if mdrw.buffered < minDataRateFlushSize {
+			return n, nil
+		}
+
+		mdrw.buffered = 0
+
+		return n, mdrw.controller.Flush()
+	})
+}
+
+// Flush sends buffered data to the client within the deadline of the minimum data rate.
+func (mdrw *minDataRateWriter) Flush() {
+	_, _ = mdrw.rate.transfer(0, func() (int, error) {
+		mdrw.buffered = 0
+
+		return 0, mdrw.controller.Flush()
+	})
+}
+
+// Hijack lets the handler take over the connection. The minimum data rate is no longer enforced.
+func (mdrw *minDataRateWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return mdrw.controller.Hijack()
+}
+
+// Unwrap returns the original response writer for http.ResponseController.
+func (mdrw *minDataRateWriter) Unwrap() http.ResponseWriter {
+	return mdrw.ResponseWriter
+}
+
+// deadlineAfter returns the deadline of the timeout. Returns the zero time if the timeout is not positive.
+func deadlineAfter(now time.Time, timeout time.Duration) time.Time {
+	if timeout <= 0 {
+		return time.Time{}
+	}
+
+	return now.Add(timeout)
+}
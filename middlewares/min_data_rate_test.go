@@ -0,0 +1,277 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/relychan/goutils"
+)
+
+func TestMinDataRateConfig_Validate(t *testing.T) {
+	if err := (MinDataRateConfig{RequestBodyBytesPerSecond: 240, ResponseBytesPerSecond: 240}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, config := range []MinDataRateConfig{
+		{RequestBodyBytesPerSecond: -1},
+		{ResponseBytesPerSecond: -1},
+		{GracePeriod: goutils.Duration(-time.Second)},
+	} {
+		if err := config.Validate(); !errors.Is(err, errMinDataRateInvalid) {
+			t.Errorf("expected errMinDataRateInvalid, got %v", err)
+		}
+	}
+}
+
+func TestMinDataRate_RequestBody(t *testing.T) {
+	config := MinDataRateConfig{
+		RequestBodyBytesPerSecond: 1000,
+		GracePeriod:               goutils.Duration(200 * time.Millisecond),
+	}
+
+	t.Run("closes the connection of a slow client", func(t *testing.T) {
+		readErr := make(chan error, 1)
+
+		server := httptest.NewServer(MinDataRate(config, 0, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := io.ReadAll(r.Body)
+			readErr <- err
+
+			if err != nil {
+				w.WriteHeader(http.StatusRequestTimeout)
+			}
+		})))
+		defer server.Close()
+
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer conn.Close()
+
+		_, err = fmt.Fprintf(conn, "POST / HTTP/1.1\r\nHost: localhost\r\nContent-Length: 10000\r\n\r\n")
+		if err != nil {
+			t.Fatalf("failed to write request: %v", err)
+		}
+
+		stop := make(chan struct{})
+		defer close(stop)
+
+		// Trickle the body with 20 bytes per second.
+		go func() {
+			ticker := time.NewTicker(50 * time.Millisecond)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-stop:
+					return
+				case <-ticker.C:
+					if _, err := conn.Write([]byte("a")); err != nil {
+						return
+					}
+				}
+			}
+		}()
+
+		select {
+		case err := <-readErr:
+			if !errors.Is(err, ErrMinDataRate) {
+				t.Errorf("expected ErrMinDataRate, got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the slow request body to fail")
+		}
+
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil {
+			t.Fatalf("failed to read response: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusRequestTimeout {
+			t.Errorf("expected status 408, got %d", resp.StatusCode)
+		}
+
+		if !resp.Close {
+			t.Error("expected the connection to be closed")
+		}
+	})
+
+	t.Run("does not penalize slow handlers", func(t *testing.T) {
+		server := httptest.NewServer(MinDataRate(config, 0, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			buf := make([]byte, 10)
+
+			if _, err := io.ReadFull(r.Body, buf); err != nil {
+				t.Errorf("failed to read body: %v", err)
+			}
+
+			// The handler spends longer than the grace period between reads.
+			time.Sleep(500 * time.Millisecond)
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("failed to read body: %v", err)
+			}
+
+			_, _ = w.Write(body)
+		})))
+		defer server.Close()
+
+		body := strings.Repeat("a", 100000)
+
+		resp, err := server.Client().Post(server.URL, "text/plain", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		respBody, _ := io.ReadAll(resp.Body)
+		if len(respBody) != len(body)-10 {
+			t.Errorf("expected %d bytes, got %d", len(body)-10, len(respBody))
+		}
+	})
+}
+
+func TestMinDataRate_Response(t *testing.T) {
+	config := MinDataRateConfig{
+		ResponseBytesPerSecond: 100 * 1024 * 1024,
+		GracePeriod:            goutils.Duration(100 * time.Millisecond),
+	}
+
+	writeErr := make(chan error, 1)
+
+	server := httptest.NewServer(MinDataRate(config, 0, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunk := bytes.Repeat([]byte("a"), 32*1024)
+
+		for range 4096 {
+			if _, err := w.Write(chunk); err != nil {
+				writeErr <- err
+
+				return
+			}
+		}
+
+		writeErr <- nil
+	})))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	// The client sends the request and never reads the response.
+	_, err = fmt.Fprintf(conn, "GET / HTTP/1.1\r\nHost: localhost\r\n\r\n")
+	if err != nil {
+		t.Fatalf("failed to write request: %v", err)
+	}
+
+	select {
+	case err := <-writeErr:
+		if !errors.Is(err, ErrMinDataRate) {
+			t.Errorf("expected ErrMinDataRate, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected the slow response to fail")
+	}
+}
+
+func TestMinDataRate_ResponseFlushed(t *testing.T) {
+	config := MinDataRateConfig{ResponseBytesPerSecond: 1024}
+
+	t.Run("keeps the content length of small responses", func(t *testing.T) {
+		server := httptest.NewServer(MinDataRate(config, 0, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("hello"))
+			_, _ = w.Write([]byte(" world"))
+		})))
+		defer server.Close()
+
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.ContentLength != int64(len("hello world")) || len(resp.TransferEncoding) > 0 {
+			t.Errorf("expected Content-Length without chunking, got %d %v", resp.ContentLength, resp.TransferEncoding)
+		}
+	})
+
+	t.Run("flushes once the buffer is full", func(t *testing.T) {
+		received := make(chan struct{})
+		chunk := bytes.Repeat([]byte("a"), 1024)
+
+		server := httptest.NewServer(MinDataRate(config, 0, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for range minDataRateFlushSize / len(chunk) {
+				_, _ = w.Write(chunk)
+			}
+
+			// The rate only applies to the bytes on the wire if the writes reach the client before the handler returns.
+			select {
+			case <-received:
+			case <-time.After(5 * time.Second):
+			}
+		})))
+		defer server.Close()
+
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer conn.Close()
+
+		_, err = fmt.Fprintf(conn, "GET / HTTP/1.1\r\nHost: localhost\r\n\r\n")
+		if err != nil {
+			t.Fatalf("failed to write request: %v", err)
+		}
+
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil {
+			t.Fatalf("expected the response to be flushed, got %v", err)
+		}
+		defer resp.Body.Close()
+
+		body := make([]byte, minDataRateFlushSize)
+		if _, err := io.ReadFull(resp.Body, body); err != nil {
+			t.Errorf("expected the flushed body, got %v", err)
+		}
+
+		close(received)
+	})
+}
+
+func TestMinDataRate_Disabled(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	if wrapped := MinDataRate(MinDataRateConfig{}, 0, 0)(handler); fmt.Sprintf("%p", wrapped) != fmt.Sprintf("%p", handler) {
+		t.Error("expected the handler to be served as is")
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"context"
+	"crypto/x509"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/relychan/gohttps/httputils"
+)
+
+type peerIdentityContextKey struct{}
+
+// PeerIdentity represents the identity of the client that is authenticated by a verified TLS client certificate.
+type PeerIdentity struct {
+	// The distinguished name of the certificate subject.
+	Subject string
+	// The common name of the certificate subject.
+	CommonName string
+	// The serial number of the certificate in hexadecimal.
+	SerialNumber string
+	// The distinguished name of the certificate issuer.
+	Issuer string
+	// DNS names of the subject alternative names.
+	DNSNames []string
+	// IP addresses of the subject alternative names.
+	IPAddresses []string
+	// Email addresses of the subject alternative names.
+	EmailAddresses []string
+	// URIs of the subject alternative names.
+	URIs []string
+	// The SPIFFE ID in the URI subject alternative names if exists.
+	SPIFFEID string
+	// The expiry time of the certificate.
+	NotAfter time.Time
+}
+
+// NewPeerIdentity creates the peer identity from the client certificate.
+func NewPeerIdentity(cert *x509.Certificate) *PeerIdentity {
+	result := &PeerIdentity{
+		Subject:        cert.Subject.String(),
+		CommonName:     cert.Subject.CommonName,
+		SerialNumber:   cert.SerialNumber.Text(16),
+		Issuer:         cert.Issuer.String(),
+		DNSNames:       cert.DNSNames,
+		EmailAddresses: cert.EmailAddresses,
+		NotAfter:       cert.NotAfter,
+	}
+
+	for _, ip := range cert.IPAddresses {
+		result.IPAddresses = append(result.IPAddresses, ip.String())
+	}
+
+	for _, uri := range cert.URIs {
+		value := uri.String()
+
+		if uri.Scheme == "spiffe" && result.SPIFFEID == "" {
+			result.SPIFFEID = value
+		}
+
+		result.URIs = append(result.URIs, value)
+	}
+
+	return result
+}
+
+// LogValue implements the slog.LogValuer interface.
+func (pi PeerIdentity) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("subject", pi.Subject),
+		slog.String("serial_number", pi.SerialNumber),
+	}
+
+	if pi.SPIFFEID != "" {
+		attrs = append(attrs, slog.String("spiffe_id", pi.SPIFFEID))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// TLSPeerIdentity stores the identity of the client from the verified TLS client certificate in the request context.
+// Read it with the GetPeerIdentity function. The request logger is enriched with the identity.
+// Unverified client certificates are ignored.
+func TLSPeerIdentity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		identity := NewPeerIdentity(r.TLS.VerifiedChains[0][0])
+		ctx := context.WithValue(r.Context(), peerIdentityContextKey{}, identity)
+		ctx = httputils.WithLogger(ctx, httputils.GetRequestLogger(r).With(slog.Any("peer_identity", identity)))
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetPeerIdentity returns the verified identity of the client from the context.
+// Returns nil if the client is not authenticated by a TLS client certificate.
+func GetPeerIdentity(ctx context.Context) *PeerIdentity {
+	identity, _ := ctx.Value(peerIdentityContextKey{}).(*PeerIdentity)
+
+	return identity
+}
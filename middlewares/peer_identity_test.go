@@ -0,0 +1,94 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestTLSPeerIdentity(t *testing.T) {
+	cert := &x509.Certificate{
+		SerialNumber:   big.NewInt(255),
+		Subject:        pkix.Name{CommonName: "client", Organization: []string{"Example"}},
+		DNSNames:       []string{"client.example.com"},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+		EmailAddresses: []string{"client@example.com"},
+		URIs: []*url.URL{
+			{Scheme: "https", Host: "example.com"},
+			{Scheme: "spiffe", Host: "example.org", Path: "/ns/default/sa/client"},
+		},
+	}
+
+	var identity *PeerIdentity
+
+	handler := TLSPeerIdentity(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity = GetPeerIdentity(r.Context())
+	}))
+
+	t.Run("verified certificate", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.TLS = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		if identity == nil {
+			t.Fatal("expected the peer identity")
+		}
+
+		if identity.CommonName != "client" || identity.SerialNumber != "ff" {
+			t.Errorf("unexpected identity: %+v", identity)
+		}
+
+		if identity.SPIFFEID != "spiffe://example.org/ns/default/sa/client" {
+			t.Errorf("unexpected SPIFFE ID: %s", identity.SPIFFEID)
+		}
+
+		if len(identity.URIs) != 2 || len(identity.IPAddresses) != 1 || identity.IPAddresses[0] != "10.0.0.1" {
+			t.Errorf("unexpected subject alternative names: %+v", identity)
+		}
+	})
+
+	t.Run("unverified certificate", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.TLS = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+		}
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		if identity != nil {
+			t.Errorf("expected no peer identity, got %+v", identity)
+		}
+	})
+
+	t.Run("plain HTTP", func(t *testing.T) {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if identity != nil {
+			t.Errorf("expected no peer identity, got %+v", identity)
+		}
+	})
+}
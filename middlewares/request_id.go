@@ -0,0 +1,187 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/relychan/gohttps/httputils"
+)
+
+const (
+	defaultRequestIDHeader    = "X-Request-Id"
+	defaultRequestIDMaxLength = 128
+)
+
+var errRequestIDInvalid = errors.New("invalid request id config")
+
+// RequestIDGenerator represents the format of generated request IDs.
+type RequestIDGenerator string
+
+const (
+	// RequestIDGeneratorUUIDv7 generates time-ordered UUIDs version 7.
+	RequestIDGeneratorUUIDv7 RequestIDGenerator = "uuidv7"
+	// RequestIDGeneratorULID generates ULIDs, 26 characters that are sortable by time.
+	RequestIDGeneratorULID RequestIDGenerator = "ulid"
+)
+
+var requestIDGenerators = []RequestIDGenerator{RequestIDGeneratorUUIDv7, RequestIDGeneratorULID}
+
+// RequestIDConfig represents the configuration of request IDs that identify requests in logs and responses.
+type RequestIDConfig struct {
+	// Store an ID in the context of every request and echo it in the response header.
+	Enabled bool `env:"SERVER_REQUEST_ID_ENABLED" json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// The header that the request ID is read from and echoed in. Default is X-Request-Id.
+	Header string `env:"SERVER_REQUEST_ID_HEADER" json:"header,omitempty" yaml:"header,omitempty"`
+	// The maximum length of inbound request IDs. Longer IDs are replaced with generated IDs. Default is 128.
+	MaxLength int `env:"SERVER_REQUEST_ID_MAX_LENGTH" json:"maxLength,omitempty" yaml:"maxLength,omitempty" jsonschema:"minimum=0"`
+	// The format of generated request IDs. Default is uuidv7.
+	Generator RequestIDGenerator `env:"SERVER_REQUEST_ID_GENERATOR" json:"generator,omitempty" yaml:"generator,omitempty" jsonschema:"enum=uuidv7,enum=ulid"`
+}
+
+// IsEnabled checks if the request ID is enabled.
+func (ric *RequestIDConfig) IsEnabled() bool {
+	return ric != nil && ric.Enabled
+}
+
+// Validate checks if the configuration is valid.
+func (ric RequestIDConfig) Validate() error {
+	if ric.MaxLength < 0 {
+		return fmt.Errorf("%w: maxLength must not be negative", errRequestIDInvalid)
+	}
+
+	if ric.Generator != "" && !slices.Contains(requestIDGenerators, ric.Generator) {
+		return fmt.Errorf("%w: unknown generator %s", errRequestIDInvalid, ric.Generator)
+	}
+
+	return nil
+}
+
+// GetHeader returns the header of request IDs. Default is X-Request-Id.
+func (ric RequestIDConfig) GetHeader() string {
+	if ric.Header != "" {
+		return http.CanonicalHeaderKey(ric.Header)
+	}
+
+	return defaultRequestIDHeader
+}
+
+// GetMaxLength returns the maximum length of inbound request IDs. Default is 128.
+func (ric RequestIDConfig) GetMaxLength() int {
+	if ric.MaxLength > 0 {
+		return ric.MaxLength
+	}
+
+	return defaultRequestIDMaxLength
+}
+
+// RequestID creates a middleware that reads the request ID from the inbound header, or generates a new ID
+// if the header is empty or invalid. The ID is stored in the request context once and echoed in the response header.
+// Read it with the httputils.GetRequestID function.
+func RequestID(config RequestIDConfig) func(http.Handler) http.Handler {
+	header := config.GetHeader()
+	maxLength := config.GetMaxLength()
+	generate := newUUIDv7
+
+	if config.Generator == RequestIDGeneratorULID {
+		generate = newULID
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(header)
+			if !isValidRequestID(requestID, maxLength) {
+				requestID = generate()
+			}
+
+			w.Header().Set(header, requestID)
+
+			next.ServeHTTP(w, r.WithContext(httputils.WithRequestID(r.Context(), requestID)))
+		})
+	}
+}
+
+// storeRequestID stores the request ID in the request context if the RequestID middleware does not run,
+// so every log line of the request has the same ID.
+func storeRequestID(r *http.Request) (*http.Request, string) {
+	requestID := httputils.RequestIDFromContext(r.Context())
+	if requestID != "" {
+		return r, requestID
+	}
+
+	requestID = httputils.GetRequestID(r)
+
+	return r.WithContext(httputils.WithRequestID(r.Context(), requestID)), requestID
+}
+
+// isValidRequestID checks if the inbound request ID is safe to log and echo.
+// Only letters, digits and the - _ . : characters are allowed.
+func isValidRequestID(requestID string, maxLength int) bool {
+	if requestID == "" || len(requestID) > maxLength {
+		return false
+	}
+
+	for _, c := range []byte(requestID) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func newUUIDv7() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.NewString()
+	}
+
+	return id.String()
+}
+
+// The Crockford's base32 alphabet of ULIDs.
+const ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newULID generates a ULID with the 48-bit timestamp in milliseconds and 80 random bits.
+func newULID() string {
+	var data [16]byte
+
+	binary.BigEndian.PutUint64(data[:8], uint64(time.Now().UnixMilli())<<16) //nolint:gosec
+	_, _ = rand.Read(data[6:])
+
+	high := binary.BigEndian.Uint64(data[:8])
+	low := binary.BigEndian.Uint64(data[8:])
+
+	// Encode 128 bits into 26 characters of 5 bits from the least significant bits.
+	result := make([]byte, 26)
+
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = ulidAlphabet[low&31]
+		low = low>>5 | high<<59
+		high >>= 5
+	}
+
+	return string(result)
+}
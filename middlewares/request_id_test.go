@@ -0,0 +1,144 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/relychan/gohttps/httputils"
+)
+
+func TestRequestID(t *testing.T) {
+	ulidPattern := regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`)
+
+	testCases := []struct {
+		Name      string
+		Config    RequestIDConfig
+		Header    string
+		Inbound   string
+		Generated bool
+		Validate  func(t *testing.T, requestID string)
+	}{
+		{
+			Name:    "inbound",
+			Inbound: "abc-123_DEF.4:5",
+		},
+		{
+			Name:      "generates uuidv7 without inbound header",
+			Generated: true,
+			Validate: func(t *testing.T, requestID string) {
+				id, err := uuid.Parse(requestID)
+				if err != nil || id.Version() != 7 {
+					t.Errorf("expected a UUIDv7, got %s", requestID)
+				}
+			},
+		},
+		{
+			Name:      "replaces invalid characters",
+			Inbound:   "abc\"<script>",
+			Generated: true,
+		},
+		{
+			Name:      "replaces long ids",
+			Config:    RequestIDConfig{MaxLength: 8},
+			Inbound:   "123456789",
+			Generated: true,
+		},
+		{
+			Name:      "custom header and ulid",
+			Config:    RequestIDConfig{Header: "x-correlation-id", Generator: RequestIDGeneratorULID},
+			Header:    "X-Correlation-Id",
+			Generated: true,
+			Validate: func(t *testing.T, requestID string) {
+				if !ulidPattern.MatchString(requestID) {
+					t.Errorf("expected a ULID, got %s", requestID)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			header := tc.Header
+			if header == "" {
+				header = "X-Request-Id"
+			}
+
+			var handlerIDs []string
+
+			handler := RequestID(tc.Config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The request ID is stable for the lifetime of the request.
+				handlerIDs = append(handlerIDs, httputils.GetRequestID(r), httputils.GetRequestID(r))
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.Inbound != "" {
+				req.Header.Set(header, tc.Inbound)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			requestID := w.Header().Get(header)
+
+			if handlerIDs[0] != requestID || handlerIDs[1] != requestID {
+				t.Errorf("expected the handler to read the echoed request ID %s, got %v", requestID, handlerIDs)
+			}
+
+			if tc.Generated == (requestID == tc.Inbound) {
+				t.Errorf("unexpected request ID %s with inbound %s", requestID, tc.Inbound)
+			}
+
+			if tc.Validate != nil {
+				tc.Validate(t, requestID)
+			}
+		})
+	}
+}
+
+func TestNewULID(t *testing.T) {
+	first := newULID()
+	second := newULID()
+
+	if first == second {
+		t.Error("expected unique ULIDs")
+	}
+
+	// The first 10 characters encode the timestamp in milliseconds.
+	if strings.Compare(first[:10], second[:10]) > 0 {
+		t.Errorf("expected ULIDs to be sorted by time, got %s and %s", first, second)
+	}
+}
+
+func TestRequestIDConfig_Validate(t *testing.T) {
+	if err := (RequestIDConfig{Generator: RequestIDGeneratorULID}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, config := range []RequestIDConfig{
+		{MaxLength: -1},
+		{Generator: "uuidv4"},
+	} {
+		if err := config.Validate(); !errors.Is(err, errRequestIDInvalid) {
+			t.Errorf("expected errRequestIDInvalid, got %v", err)
+		}
+	}
+}
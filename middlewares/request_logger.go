@@ -0,0 +1,125 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/relychan/gohttps/httputils"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestLogger creates a middleware that stores the logger in the context of every request.
+// The logger is enriched with the request ID, client IP, route pattern and the trace and span IDs.
+// The request ID is generated and stored in the context once if the RequestID middleware does not run.
+// The ClientIP and tracing middlewares must run before this middleware to be included.
+// Read the logger with the httputils.GetRequestLogger function.
+func RequestLogger(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r, requestID := storeRequestID(r)
+			ctx := r.Context()
+
+			attrs := []any{
+				slog.String("request_id", requestID),
+				slog.String("client_ip", clientIP(r)),
+			}
+
+			// Unsampled traces are never exported, so logs only link to sampled traces.
+			if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() && spanContext.IsSampled() {
+				attrs = append(
+					attrs,
+					slog.String("trace_id", spanContext.TraceID().String()),
+					slog.String("span_id", spanContext.SpanID().String()),
+				)
+			}
+
+			routeContext := chi.RouteContext(ctx)
+			if routeContext == nil {
+				next.ServeHTTP(w, r.WithContext(httputils.WithLogger(ctx, logger.With(attrs...))))
+
+				return
+			}
+
+			// The route is matched after middlewares, so the pattern is added when a line is logged.
+			route := &lazyRoutePattern{routeContext: routeContext}
+			requestLogger := slog.New(routePatternHandler{Handler: logger.Handler(), route: route})
+
+			// chi reuses the route context after the request, so the logger keeps a copy of the final pattern,
+			// even if the handler panics.
+			defer route.finish()
+
+			next.ServeHTTP(w, r.WithContext(httputils.WithLogger(ctx, requestLogger.With(attrs...))))
+		})
+	}
+}
+
+// lazyRoutePattern resolves the route pattern of chi for loggers that may outlive the request.
+// The route context is released once the request finishes.
+type lazyRoutePattern struct {
+	mu           sync.Mutex
+	routeContext *chi.Context
+	pattern      string
+}
+
+func (rp *lazyRoutePattern) get() string {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	if rp.routeContext == nil {
+		return rp.pattern
+	}
+
+	return rp.routeContext.RoutePattern()
+}
+
+func (rp *lazyRoutePattern) finish() {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	rp.pattern = rp.routeContext.RoutePattern()
+	rp.routeContext = nil
+}
+
+// routePatternHandler adds the route pattern of chi to log records once the route is matched.
+type routePatternHandler struct {
+	slog.Handler
+
+	route *lazyRoutePattern
+}
+
+// Handle implements the slog.Handler interface.
+func (rph routePatternHandler) Handle(ctx context.Context, record slog.Record) error {
+	if pattern := rph.route.get(); pattern != "" {
+		record = record.Clone()
+		record.AddAttrs(slog.String("route", pattern))
+	}
+
+	return rph.Handler.Handle(ctx, record)
+}
+
+// WithAttrs implements the slog.Handler interface.
+func (rph routePatternHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return routePatternHandler{Handler: rph.Handler.WithAttrs(attrs), route: rph.route}
+}
+
+// WithGroup implements the slog.Handler interface.
+func (rph routePatternHandler) WithGroup(name string) slog.Handler {
+	return routePatternHandler{Handler: rph.Handler.WithGroup(name), route: rph.route}
+}
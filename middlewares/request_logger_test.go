@@ -0,0 +1,144 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/relychan/gohttps/httputils"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	router := chi.NewRouter()
+	router.Use(RequestID(RequestIDConfig{}))
+	router.Use(RequestLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	router.Use(TLSPeerIdentity)
+	router.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		httputils.GetRequestLogger(r).Info("hello")
+	})
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(t.Context(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/users/42", nil)
+	req.RemoteAddr = "203.0.113.10:1234"
+	req.Header.Set("X-Request-Id", "request-1")
+	req.TLS = &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{
+			Subject:      pkix.Name{CommonName: "client"},
+			SerialNumber: big.NewInt(10),
+		}}},
+	}
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	logs := readAccessLogs(t, &buf)
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 log line, got %d", len(logs))
+	}
+
+	for key, expected := range map[string]any{
+		"msg":        "hello",
+		"request_id": "request-1",
+		"client_ip":  "203.0.113.10",
+		"route":      "/users/{id}",
+		"trace_id":   traceID.String(),
+		"span_id":    spanID.String(),
+	} {
+		if logs[0][key] != expected {
+			t.Errorf("expected %s to be %v, got %v", key, expected, logs[0][key])
+		}
+	}
+
+	peerIdentity, _ := logs[0]["peer_identity"].(map[string]any)
+	if peerIdentity["subject"] != "CN=client" {
+		t.Errorf("expected the peer identity to be logged, got %v", logs[0]["peer_identity"])
+	}
+}
+
+func TestRequestLoggerUnsampledTrace(t *testing.T) {
+	var buf bytes.Buffer
+
+	ratio := 0.0
+
+	router := chi.NewRouter()
+	router.Use(Tracing(TracingConfig{Enabled: true, SampleRatio: &ratio}, tracenoop.NewTracerProvider()))
+	router.Use(RequestLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+		httputils.GetRequestLogger(r).Info("hello")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	logs := readAccessLogs(t, &buf)
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 log line, got %d", len(logs))
+	}
+
+	if logs[0]["trace_id"] != nil || logs[0]["span_id"] != nil {
+		t.Errorf("expected no trace of the unsampled request, got %v", logs[0])
+	}
+}
+
+func TestRequestLoggerPanic(t *testing.T) {
+	var buf bytes.Buffer
+
+	var requestLogger *slog.Logger
+
+	router := chi.NewRouter()
+	router.Use(middleware.Recoverer)
+	router.Use(RequestLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	router.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		requestLogger = httputils.GetRequestLogger(r)
+
+		panic("boom")
+	})
+
+	router.Get("/other", func(w http.ResponseWriter, r *http.Request) {})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	// The route context returns to the pool of chi, then serves other requests.
+	for range 3 {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/other", nil))
+	}
+
+	buf.Reset()
+	requestLogger.Info("after the request")
+
+	logs := readAccessLogs(t, &buf)
+	if len(logs) != 1 || logs[0]["route"] != "/users/{id}" {
+		t.Errorf("expected the route of the panicked request, got %v", logs)
+	}
+}
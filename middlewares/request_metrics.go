@@ -0,0 +1,241 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/semconv/v1.41.0/httpconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/relychan/gohttps/middlewares"
+
+// The attribute of the class of the response status code, e.g. 2xx or 5xx.
+// The class keeps the cardinality of the metrics low compared with the status code.
+const httpResponseStatusClassKey = attribute.Key("http.response.status_class")
+
+// RequestMetricsConfig represents the configuration of the RED (rate, errors and duration) metrics of HTTP requests.
+type RequestMetricsConfig struct {
+	// Record the metrics of HTTP requests that follow the OpenTelemetry semantic conventions.
+	Enabled bool `env:"SERVER_REQUEST_METRICS_ENABLED" json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// List of URL paths that are not recorded, e.g. /metrics or /healthz.
+	// If not set, the server excludes the metrics and health check endpoints.
+	ExcludedPaths []string `env:"SERVER_REQUEST_METRICS_EXCLUDED_PATHS" json:"excludedPaths,omitempty" yaml:"excludedPaths,omitempty"`
+}
+
+// IsEnabled checks if the request metrics are enabled.
+func (rmc *RequestMetricsConfig) IsEnabled() bool {
+	return rmc != nil && rmc.Enabled
+}
+
+type requestMetrics struct {
+	excludedPaths    []string
+	duration         httpconv.ServerRequestDuration
+	requestBodySize  httpconv.ServerRequestBodySize
+	responseBodySize httpconv.ServerResponseBodySize
+	activeRequests   httpconv.ServerActiveRequests
+}
+
+// RequestMetrics creates a middleware that records the duration, body sizes and active requests of HTTP requests.
+// Metrics are labelled by the route pattern of chi instead of the raw path, the method and the class of the status code.
+// Values are recorded with the trace context of requests, so the meter provider can link exemplars to trace IDs.
+func RequestMetrics(config RequestMetricsConfig, meterProvider metric.MeterProvider) (func(http.Handler) http.Handler, error) {
+	meter := meterProvider.Meter(instrumentationName)
+
+	duration, err := httpconv.NewServerRequestDuration(meter)
+	if err != nil {
+		return nil, err
+	}
+
+	requestBodySize, err := httpconv.NewServerRequestBodySize(meter)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBodySize, err := httpconv.NewServerResponseBodySize(meter)
+	if err != nil {
+		return nil, err
+	}
+
+	activeRequests, err := httpconv.NewServerActiveRequests(meter)
+	if err != nil {
+		return nil, err
+	}
+
+	rm := &requestMetrics{
+		excludedPaths:    config.ExcludedPaths,
+		duration:         duration,
+		requestBodySize:  requestBodySize,
+		responseBodySize: responseBodySize,
+		activeRequests:   activeRequests,
+	}
+
+	return rm.handle, nil
+}
+
+func (rm *requestMetrics) handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if slices.Contains(rm.excludedPaths, r.URL.Path) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		startTime := time.Now()
+		ctx := exemplarContext(r)
+		method := requestMethod(r.Method)
+		scheme := requestScheme(r)
+
+		rm.activeRequests.Add(ctx, 1, method, scheme)
+		defer rm.activeRequests.Add(ctx, -1, method, scheme)
+
+		var body *countingReader
+
+		if r.Body != nil && r.Body != http.NoBody {
+			body = &countingReader{ReadCloser: r.Body}
+			r.Body = body
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		statusCode := ww.Status()
+		if statusCode == 0 {
+			statusCode = http.StatusOK
+		}
+
+		attrs := make([]attribute.KeyValue, 0, 4)
+		attrs = append(
+			attrs,
+			httpResponseStatusClassKey.String(statusClass(statusCode)),
+			semconv.NetworkProtocolVersion(protocolVersion(r)),
+		)
+
+		if route := routePattern(r); route != "" {
+			attrs = append(attrs, semconv.HTTPRoute(route))
+		}
+
+		if statusCode >= http.StatusInternalServerError {
+			attrs = append(attrs, semconv.ErrorTypeKey.String(strconv.Itoa(statusCode)))
+		}
+
+		var requestBytes int64
+
+		if body != nil {
+			requestBytes = body.bytes
+		}
+
+		rm.duration.Record(ctx, time.Since(startTime).Seconds(), method, scheme, attrs...)
+		rm.requestBodySize.Record(ctx, requestBytes, method, scheme, attrs...)
+		rm.responseBodySize.Record(ctx, int64(ww.BytesWritten()), method, scheme, attrs...)
+	})
+}
+
+// exemplarContext returns the context that metrics are recorded with.
+// If no span is started yet, the trace context is extracted from the request headers with the global propagator.
+// Unsampled trace contexts are removed because exemplars must not link to traces that are never exported.
+func exemplarContext(r *http.Request) context.Context {
+	ctx := r.Context()
+
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+	}
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() && !spanContext.IsSampled() {
+		return trace.ContextWithSpanContext(ctx, trace.SpanContext{})
+	}
+
+	return ctx
+}
+
+// routePattern returns the matched route pattern of chi, or an empty string if no route matches.
+func routePattern(r *http.Request) string {
+	routeContext := chi.RouteContext(r.Context())
+	if routeContext == nil {
+		return ""
+	}
+
+	return routeContext.RoutePattern()
+}
+
+func requestMethod(method string) httpconv.RequestMethodAttr {
+	switch method {
+	case http.MethodConnect:
+		return httpconv.RequestMethodConnect
+	case http.MethodDelete:
+		return httpconv.RequestMethodDelete
+	case http.MethodGet:
+		return httpconv.RequestMethodGet
+	case http.MethodHead:
+		return httpconv.RequestMethodHead
+	case http.MethodOptions:
+		return httpconv.RequestMethodOptions
+	case http.MethodPatch:
+		return httpconv.RequestMethodPatch
+	case http.MethodPost:
+		return httpconv.RequestMethodPost
+	case http.MethodPut:
+		return httpconv.RequestMethodPut
+	case http.MethodTrace:
+		return httpconv.RequestMethodTrace
+	default:
+		// Unknown methods are grouped to keep the cardinality bounded.
+		return httpconv.RequestMethodOther
+	}
+}
+
+func requestScheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+
+	return "http"
+}
+
+func protocolVersion(r *http.Request) string {
+	if r.ProtoMajor >= 2 {
+		return strconv.Itoa(r.ProtoMajor)
+	}
+
+	return strconv.Itoa(r.ProtoMajor) + "." + strconv.Itoa(r.ProtoMinor)
+}
+
+// countingReader counts the bytes read from the request body.
+type countingReader struct {
+	io.ReadCloser
+
+	bytes int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.ReadCloser.Read(p)
+	cr.bytes += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRequestMetrics(t *testing.T) {
+	defaultPropagator := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTextMapPropagator(defaultPropagator)
+	})
+
+	reader := sdkmetric.NewManualReader()
+
+	requestMetrics, err := RequestMetrics(
+		RequestMetricsConfig{Enabled: true, ExcludedPaths: []string{"/healthz"}},
+		sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	)
+	if err != nil {
+		t.Fatalf("failed to create the middleware: %v", err)
+	}
+
+	router := chi.NewRouter()
+	router.Use(requestMetrics)
+	router.Post("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		buf := make([]byte, 64)
+		n, _ := r.Body.Read(buf)
+
+		_, _ = w.Write(buf[:n])
+	})
+	router.Get("/error", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	router.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {})
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	req := httptest.NewRequest(http.MethodPost, "/users/42", strings.NewReader("hello"))
+	req.Header.Set("Traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/error", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PURGE", "/unknown", nil))
+
+	var data metricdata.ResourceMetrics
+
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	metrics := map[string]metricdata.Aggregation{}
+
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	t.Run("duration", func(t *testing.T) {
+		points := metrics["http.server.request.duration"].(metricdata.Histogram[float64]).DataPoints
+		if len(points) != 3 {
+			t.Fatalf("expected 3 data points, got %d", len(points))
+		}
+
+		routes := map[string]metricdata.HistogramDataPoint[float64]{}
+
+		for _, point := range points {
+			route, _ := point.Attributes.Value("http.route")
+			routes[route.AsString()] = point
+		}
+
+		user, ok := routes["/users/{id}"]
+		if !ok {
+			t.Fatalf("expected the route pattern to be recorded, got %v", routes)
+		}
+
+		for key, expected := range map[string]string{
+			"http.request.method":        "POST",
+			"http.response.status_class": "2xx",
+			"url.scheme":                 "http",
+			"network.protocol.version":   "1.1",
+		} {
+			value, _ := user.Attributes.Value(attribute.Key(key))
+			if value.AsString() != expected {
+				t.Errorf("expected %s to be %s, got %s", key, expected, value.AsString())
+			}
+		}
+
+		if len(user.Exemplars) != 1 || hex.EncodeToString(user.Exemplars[0].TraceID) != traceID {
+			t.Errorf("expected an exemplar with the trace ID %s, got %v", traceID, user.Exemplars)
+		}
+
+		errorPoint := routes["/error"]
+
+		if value, _ := errorPoint.Attributes.Value("http.response.status_class"); value.AsString() != "5xx" {
+			t.Errorf("expected status class 5xx, got %s", value.AsString())
+		}
+
+		if value, _ := errorPoint.Attributes.Value("error.type"); value.AsString() != "500" {
+			t.Errorf("expected error type 500, got %s", value.AsString())
+		}
+
+		unknown, ok := routes[""]
+		if !ok {
+			t.Fatal("expected unmatched requests to be recorded without the route")
+		}
+
+		if value, _ := unknown.Attributes.Value("http.request.method"); value.AsString() != "_OTHER" {
+			t.Errorf("expected method _OTHER, got %s", value.AsString())
+		}
+	})
+
+	t.Run("body_sizes", func(t *testing.T) {
+		for name, expected := range map[string]int64{
+			"http.server.request.body.size":  5,
+			"http.server.response.body.size": 5,
+		} {
+			var sum int64
+
+			for _, point := range metrics[name].(metricdata.Histogram[int64]).DataPoints {
+				sum += point.Sum
+			}
+
+			if sum != expected {
+				t.Errorf("expected %s to sum %d, got %d", name, expected, sum)
+			}
+		}
+	})
+
+	t.Run("active_requests", func(t *testing.T) {
+		for _, point := range metrics["http.server.active_requests"].(metricdata.Sum[int64]).DataPoints {
+			if point.Value != 0 {
+				t.Errorf("expected no active requests, got %d", point.Value)
+			}
+		}
+	})
+}
+
+func TestRequestMetricsConfig_IsEnabled(t *testing.T) {
+	var config *RequestMetricsConfig
+
+	if config.IsEnabled() {
+		t.Error("expected nil config to be disabled")
+	}
+
+	if !(&RequestMetricsConfig{Enabled: true}).IsEnabled() {
+		t.Error("expected config to be enabled")
+	}
+}
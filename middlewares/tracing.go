@@ -0,0 +1,230 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/semconv/v1.41.0/httpconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var errTracingInvalid = errors.New("invalid tracing config")
+
+// TracingConfig represents the configuration of the OpenTelemetry tracing of HTTP requests.
+type TracingConfig struct {
+	// Start a server span for every HTTP request that follows the OpenTelemetry semantic conventions.
+	Enabled bool `env:"SERVER_TRACING_ENABLED" json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// The ratio of requests without a parent span that are sampled, between 0 and 1. Default is 1.
+	// Requests with a parent span in the traceparent header follow the sampling decision of the parent.
+	SampleRatio *float64 `env:"SERVER_TRACING_SAMPLE_RATIO" json:"sampleRatio,omitempty" yaml:"sampleRatio,omitempty" jsonschema:"minimum=0,maximum=1"`
+	// List of URL paths that are not traced, e.g. /metrics or /healthz.
+	// If not set, the server excludes the metrics and health check endpoints.
+	ExcludedPaths []string `env:"SERVER_TRACING_EXCLUDED_PATHS" json:"excludedPaths,omitempty" yaml:"excludedPaths,omitempty"`
+}
+
+// IsEnabled checks if the tracing is enabled.
+func (tc *TracingConfig) IsEnabled() bool {
+	return tc != nil && tc.Enabled
+}
+
+// Validate checks if the configuration is valid.
+func (tc TracingConfig) Validate() error {
+	if tc.SampleRatio != nil && (*tc.SampleRatio < 0 || *tc.SampleRatio > 1) {
+		return fmt.Errorf("%w: sampleRatio must be between 0 and 1", errTracingInvalid)
+	}
+
+	return nil
+}
+
+// GetSampleRatio returns the ratio of requests without a parent span that are sampled. Default is 1.
+func (tc TracingConfig) GetSampleRatio() float64 {
+	if tc.SampleRatio != nil {
+		return *tc.SampleRatio
+	}
+
+	return 1
+}
+
+type tracing struct {
+	tracer        trace.Tracer
+	propagator    propagation.TextMapPropagator
+	sampleRatio   float64
+	excludedPaths []string
+}
+
+// Tracing creates a middleware that starts a server span for every HTTP request.
+// The trace context and baggage are extracted from the W3C traceparent and baggage headers.
+// Spans are named by the route pattern of chi and include the client IP that the ClientIP middleware resolves.
+func Tracing(config TracingConfig, tracerProvider trace.TracerProvider) func(http.Handler) http.Handler {
+	tm := &tracing{
+		tracer: tracerProvider.Tracer(instrumentationName),
+		propagator: propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		),
+		sampleRatio:   config.GetSampleRatio(),
+		excludedPaths: config.ExcludedPaths,
+	}
+
+	return tm.handle
+}
+
+func (tm *tracing) handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if slices.Contains(tm.excludedPaths, r.URL.Path) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		ctx := tm.propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		if !trace.SpanContextFromContext(ctx).IsValid() && !tm.shouldSample() {
+			// Store the sampling decision, so spans of handlers and downstream services are not sampled either.
+			next.ServeHTTP(w, r.WithContext(trace.ContextWithSpanContext(ctx, newUnsampledSpanContext())))
+
+			return
+		}
+
+		method := requestMethod(r.Method)
+
+		ctx, span := tm.tracer.Start(
+			ctx,
+			spanName(method, ""),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(requestAttributes(r, method)...),
+		)
+		defer span.End()
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		statusCode := ww.Status()
+		if statusCode == 0 {
+			statusCode = http.StatusOK
+		}
+
+		span.SetAttributes(
+			semconv.HTTPResponseStatusCode(statusCode),
+			semconv.HTTPResponseBodySize(ww.BytesWritten()),
+		)
+
+		if route := routePattern(r); route != "" {
+			span.SetName(spanName(method, route))
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+
+		if statusCode >= http.StatusInternalServerError {
+			span.SetAttributes(semconv.ErrorTypeKey.String(strconv.Itoa(statusCode)))
+			span.SetStatus(codes.Error, http.StatusText(statusCode))
+		}
+	})
+}
+
+func (tm *tracing) shouldSample() bool {
+	switch {
+	case tm.sampleRatio >= 1:
+		return true
+	case tm.sampleRatio <= 0:
+		return false
+	default:
+		return rand.Float64() < tm.sampleRatio //nolint:gosec
+	}
+}
+
+// spanName returns the span name of a request, e.g. GET /users/{id}.
+func spanName(method httpconv.RequestMethodAttr, route string) string {
+	name := string(method)
+	if method == httpconv.RequestMethodOther {
+		name = "HTTP"
+	}
+
+	if route == "" {
+		return name
+	}
+
+	return name + " " + route
+}
+
+func requestAttributes(r *http.Request, method httpconv.RequestMethodAttr) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(string(method)),
+		semconv.URLPath(r.URL.Path),
+		semconv.URLScheme(requestScheme(r)),
+		semconv.NetworkProtocolVersion(protocolVersion(r)),
+	}
+
+	if host, port, err := net.SplitHostPort(r.Host); err == nil {
+		attrs = append(attrs, semconv.ServerAddress(host))
+
+		if portNumber, err := strconv.Atoi(port); err == nil {
+			attrs = append(attrs, semconv.ServerPort(portNumber))
+		}
+	} else if r.Host != "" {
+		attrs = append(attrs, semconv.ServerAddress(r.Host))
+	}
+
+	peerAddress, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err == nil {
+		attrs = append(attrs, semconv.NetworkPeerAddress(peerAddress))
+	}
+
+	// The ClientIP middleware must run before this middleware to resolve the client IP behind proxies.
+	if clientIP := middleware.GetClientIP(r.Context()); clientIP != "" {
+		attrs = append(attrs, semconv.ClientAddress(clientIP))
+	} else if peerAddress != "" {
+		attrs = append(attrs, semconv.ClientAddress(peerAddress))
+	}
+
+	if userAgent := r.UserAgent(); userAgent != "" {
+		attrs = append(attrs, semconv.UserAgentOriginal(userAgent))
+	}
+
+	if r.ContentLength > 0 {
+		attrs = append(attrs, semconv.HTTPRequestBodySize(int(r.ContentLength)))
+	}
+
+	return attrs
+}
+
+func newUnsampledSpanContext() trace.SpanContext {
+	var (
+		traceID trace.TraceID
+		spanID  trace.SpanID
+	)
+
+	binary.BigEndian.PutUint64(traceID[:8], rand.Uint64()) //nolint:gosec
+	binary.BigEndian.PutUint64(traceID[8:], rand.Uint64()) //nolint:gosec
+	binary.BigEndian.PutUint64(spanID[:], rand.Uint64())   //nolint:gosec
+
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	})
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTracingTestRouter(config TracingConfig, recorder *tracetest.SpanRecorder) *chi.Mux {
+	router := chi.NewRouter()
+	router.Use(ClientIP(&ClientIPConfig{Type: ClientIPFromHeader, Headers: []string{"X-Real-IP"}}))
+	router.Use(Tracing(config, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	router.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(baggage.FromContext(r.Context()).Member("tenant").Value()))
+	})
+	router.Get("/error", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	router.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {})
+	router.Get("/trace", func(w http.ResponseWriter, r *http.Request) {
+		spanContext := trace.SpanContextFromContext(r.Context())
+		if !spanContext.IsValid() || spanContext.IsSampled() {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	return router
+}
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	router := newTracingTestRouter(TracingConfig{Enabled: true, ExcludedPaths: []string{"/healthz"}}, recorder)
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set("Traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	req.Header.Set("Baggage", "tenant=acme")
+	req.Header.Set("X-Real-IP", "203.0.113.10")
+	req.Header.Set("User-Agent", "test")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Body.String() != "acme" {
+		t.Errorf("expected the baggage to be propagated, got %q", w.Body.String())
+	}
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/error", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	t.Run("route_pattern", func(t *testing.T) {
+		span := spans[0]
+
+		if span.Name() != "GET /users/{id}" {
+			t.Errorf("expected span name GET /users/{id}, got %s", span.Name())
+		}
+
+		if span.SpanKind() != trace.SpanKindServer {
+			t.Errorf("expected a server span, got %s", span.SpanKind())
+		}
+
+		if span.SpanContext().TraceID().String() != traceID {
+			t.Errorf("expected trace ID %s, got %s", traceID, span.SpanContext().TraceID())
+		}
+
+		attrs := attribute.NewSet(span.Attributes()...)
+
+		for key, expected := range map[string]string{
+			"http.request.method": "GET",
+			"http.route":          "/users/{id}",
+			"url.path":            "/users/42",
+			"client.address":      "203.0.113.10",
+			"user_agent.original": "test",
+		} {
+			value, _ := attrs.Value(attribute.Key(key))
+			if value.Emit() != expected {
+				t.Errorf("expected %s to be %s, got %s", key, expected, value.Emit())
+			}
+		}
+
+		if value, _ := attrs.Value("http.response.status_code"); value.AsInt64() != http.StatusOK {
+			t.Errorf("expected status code 200, got %d", value.AsInt64())
+		}
+	})
+
+	t.Run("server_error", func(t *testing.T) {
+		span := spans[1]
+
+		if span.Status().Code != codes.Error {
+			t.Errorf("expected error status, got %s", span.Status().Code)
+		}
+
+		if span.Parent().IsValid() {
+			t.Error("expected a root span")
+		}
+	})
+}
+
+func TestTracing_Sampling(t *testing.T) {
+	ratio := 0.0
+	recorder := tracetest.NewSpanRecorder()
+	router := newTracingTestRouter(TracingConfig{Enabled: true, SampleRatio: &ratio}, recorder)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/trace", nil))
+
+	if w.Code != http.StatusOK {
+		t.Error("expected an unsampled span context in the request context")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/trace", nil)
+	req.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected only the request with a sampled parent to be traced, got %d spans", len(spans))
+	}
+}
+
+func TestTracingConfig_Validate(t *testing.T) {
+	for _, ratio := range []float64{-0.1, 1.1} {
+		if err := (TracingConfig{SampleRatio: &ratio}).Validate(); !errors.Is(err, errTracingInvalid) {
+			t.Errorf("expected errTracingInvalid for ratio %f, got %v", ratio, err)
+		}
+	}
+
+	ratio := 0.5
+
+	if err := (TracingConfig{SampleRatio: &ratio}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if (TracingConfig{}).GetSampleRatio() != 1 {
+		t.Error("expected the default sample ratio to be 1")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"net"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ServerOption is a function to modify the server options.
+type ServerOption func(*serverOptions)
+
+type serverOptions struct {
+	healthRegistry *HealthRegistry
+	meterProvider  metric.MeterProvider
+	listeners      []net.Listener
+	upgradeArgs    []string
+}
+
+func newServerOptions(options []ServerOption) *serverOptions {
+	result := &serverOptions{}
+
+	for _, opt := range options {
+		opt(result)
+	}
+
+	if result.healthRegistry == nil {
+		result.healthRegistry = NewHealthRegistry()
+	}
+
+	if result.meterProvider == nil {
+		result.meterProvider = otel.GetMeterProvider()
+	}
+
+	return result
+}
+
+// WithHealthRegistry sets the health registry that serves the liveness, readiness and startup probes.
+func WithHealthRegistry(registry *HealthRegistry) ServerOption {
+	return func(so *serverOptions) {
+		so.healthRegistry = registry
+	}
+}
+
+// WithMeterProvider sets the OpenTelemetry meter provider to record metrics of the server.
+// Default is the global meter provider.
+func WithMeterProvider(meterProvider metric.MeterProvider) ServerOption {
+	return func(so *serverOptions) {
+		so.meterProvider = meterProvider
+	}
+}
+
+// WithListener adds a listener that the server accepts connections from instead of listening to the configured addresses.
+// It is useful to embed the server in tests with a random port, e.g. net.Listen("tcp", "127.0.0.1:0").
+func WithListener(listener net.Listener) ServerOption {
+	return func(so *serverOptions) {
+		so.listeners = append(so.listeners, listener)
+	}
+}
+
+// WithUpgradeArgs sets the arguments of the new process that the listeners are handed off to during an upgrade.
+// Default is the arguments of the current process.
+func WithUpgradeArgs(args ...string) ServerOption {
+	return func(so *serverOptions) {
+		so.upgradeArgs = args
+	}
+}
@@ -17,10 +17,8 @@ package gohttps
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log/slog"
-	"net"
 	"net/http"
 	"os"
 	"strconv"
@@ -31,20 +29,83 @@ import (
 	"github.com/go-chi/cors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/relychan/gohttps/middlewares"
+	"go.opentelemetry.io/otel"
 )
 
 // NewRouter creates a new router with default middlewares.
+// The logger is stored in the context of every request. Read it with the httputils.GetRequestLogger function.
+// The default logger is used if the logger is nil.
 func NewRouter(config *ServerConfig, logger *slog.Logger) *chi.Mux {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
 	router := chi.NewRouter()
 
+	if config != nil {
+		// The request ID is stored first, so all other middlewares log the same ID.
+		if config.RequestID.IsEnabled() {
+			router.Use(middlewares.RequestID(*config.RequestID))
+		}
+
+		// The client IP is resolved before the tracing middleware, so the tracing middleware can record it.
+		if config.ClientIP != nil {
+			router.Use(middlewares.ClientIP(config.ClientIP))
+		}
+
+		// The tracing middleware wraps the request metrics, so exemplars link to the spans of requests.
+		if config.Tracing.IsEnabled() {
+			tracingConfig := *config.Tracing
+			tracingConfig.ExcludedPaths = getExcludedPaths(tracingConfig.ExcludedPaths)
+
+			router.Use(middlewares.Tracing(tracingConfig, otel.GetTracerProvider()))
+		}
+	}
+
+	// The logger is stored after the request ID, client IP and tracing middlewares, so it is enriched with their values.
+	router.Use(middlewares.RequestLogger(logger))
+
+	if config != nil {
+		// The access log runs inside the tracing middleware to log the trace ID.
+		if config.AccessLog.IsEnabled() {
+			accessLogConfig := *config.AccessLog
+			accessLogConfig.ExcludedPaths = getExcludedPaths(accessLogConfig.ExcludedPaths)
+
+			router.Use(middlewares.AccessLog(accessLogConfig, logger))
+		}
+
+		// Request metrics wrap the remaining middlewares, so the duration includes the time that they spend.
+		// The server still serves requests without metrics if the instruments cannot be created.
+		if config.RequestMetrics.IsEnabled() {
+			requestMetricsConfig := *config.RequestMetrics
+			requestMetricsConfig.ExcludedPaths = getExcludedPaths(requestMetricsConfig.ExcludedPaths)
+
+			requestMetrics, err := middlewares.RequestMetrics(requestMetricsConfig, otel.GetMeterProvider())
+			if err != nil {
+				logger.Error("failed to create the request metrics middleware", slog.String("error", err.Error()))
+			} else {
+				router.Use(requestMetrics)
+			}
+		}
+
+		// The minimum data rates apply to bytes on the wire, so the middleware wraps decompression and compression.
+		if config.MinDataRate != nil {
+			router.Use(middlewares.MinDataRate(
+				*config.MinDataRate,
+				time.Duration(config.ReadTimeout),
+				time.Duration(config.WriteTimeout),
+			))
+		}
+	}
+
 	router.Use(middlewares.Decompress)
 
 	if config == nil {
 		return router
 	}
 
-	if config.ClientIP != nil {
-		router.Use(middlewares.ClientIP(config.ClientIP))
+	if config.TLS != nil && config.TLS.IsClientCertVerified() {
+		router.Use(middlewares.TLSPeerIdentity)
 	}
 
 	if config.RequestTimeout > 0 {
@@ -82,88 +143,64 @@ func NewRouter(config *ServerConfig, logger *slog.Logger) *chi.Mux {
 	return router
 }
 
-// ListenAndServe listens and serves the HTTP server.
-func ListenAndServe(ctx context.Context, router *chi.Mux, config *ServerConfig) error {
-	if config == nil {
-		return errServerConfigRequired
+// getExcludedPaths returns the paths that the tracing, access log and request metrics skip. Default is the metrics and health check endpoints.
+func getExcludedPaths(paths []string) []string {
+	if paths != nil {
+		return paths
 	}
 
-	router.Get(pathHealthz, func(w http.ResponseWriter, _ *http.Request) {
-		_, err := w.Write([]byte("OK"))
-		if err != nil {
-			slog.Error("failed to write response: " + err.Error())
-		}
-	})
-
-	serverErr := make(chan error, 1)
+	return []string{pathMetrics, pathHealthz, pathLivez, pathReadyz, pathStartupz}
+}
 
-	// setup prometheus handler if enabled
-	promServer, err := CreatePrometheusServer(router, config.GetPort())
+// ListenAndServe listens and serves the HTTP server until the context is cancelled, then shuts it down gracefully.
+func ListenAndServe(
+	ctx context.Context,
+	router *chi.Mux,
+	config *ServerConfig,
+	options ...ServerOption,
+) error {
+	server, err := NewServer(config, router, options...)
 	if err != nil {
 		return err
 	}
 
-	if promServer != nil {
-		defer func() { //nolint:contextcheck
-			err := promServer.Shutdown(context.Background())
-			if err != nil && !errors.Is(err, http.ErrServerClosed) {
-				slog.Warn("failed to shutdown prometheus server: " + err.Error())
-			}
-		}()
-
-		go func() {
-			err := promServer.ListenAndServe()
-			if !errors.Is(err, http.ErrServerClosed) {
-				serverErr <- err
-			}
-		}()
+	err = server.Start(ctx)
+	if err != nil {
+		return err
 	}
 
-	maxHeaderBytes := http.DefaultMaxHeaderBytes
+	var upgradeSignal <-chan os.Signal
 
-	if config.MaxHeaderKilobytes > 0 {
-		maxHeaderBytes = config.MaxHeaderKilobytes * kilobyte
-	}
+	if config.UpgradeOnSignal {
+		var stop func()
 
-	server := http.Server{
-		Addr: ":" + strconv.Itoa(config.GetPort()),
-		BaseContext: func(_ net.Listener) context.Context {
-			return ctx
-		},
-		Handler:           router,
-		ReadTimeout:       time.Duration(config.ReadTimeout),
-		ReadHeaderTimeout: time.Duration(config.ReadHeaderTimeout),
-		WriteTimeout:      time.Duration(config.WriteTimeout),
-		IdleTimeout:       time.Duration(config.IdleTimeout),
-		MaxHeaderBytes:    maxHeaderBytes,
+		upgradeSignal, stop = notifyUpgradeSignal()
+		defer stop()
 	}
 
-	go func() {
-		var err error
+	for {
+		// Wait for interruption.
+		select {
+		case <-server.Done():
+			// Error when serving HTTP requests.
+			return server.Err()
+		case <-upgradeSignal:
+			slog.Info("received the upgrade signal, handing off listeners to a new process...")
 
-		if config.TLSCertFile != "" || config.TLSKeyFile != "" {
-			slog.Info("Listening server and serving TLS on " + server.Addr)
-			err = server.ListenAndServeTLS(config.TLSCertFile, config.TLSKeyFile)
-		} else {
-			slog.Info("Listening server on " + server.Addr)
-			err = server.ListenAndServe()
-		}
+			err := server.Upgrade(ctx)
+			if err != nil {
+				slog.Error("failed to upgrade the server: " + err.Error())
 
-		if err != nil && !errors.Is(err, http.ErrServerClosed) {
-			serverErr <- err
-		}
-	}()
+				continue
+			}
 
-	// Wait for interruption.
-	select {
-	case err := <-serverErr:
-		// Error when starting HTTP server.
-		return err
-	case <-ctx.Done():
-		// Wait for first CTRL+C.
-		slog.Info("received the quit signal, exiting...")
-		// When Shutdown is called, ListenAndServe immediately returns ErrServerClosed.
-		return server.Shutdown(context.Background()) //nolint:contextcheck
+			return server.Shutdown(context.WithoutCancel(ctx))
+		case <-ctx.Done():
+			// Wait for first CTRL+C.
+			slog.Info("received the quit signal, exiting...")
+
+			return server.Shutdown(context.WithoutCancel(ctx))
+		}
 	}
 }
 
@@ -16,13 +16,18 @@ package gohttps
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/relychan/gohttps/middlewares"
 	"github.com/relychan/goutils"
 )
 
@@ -93,6 +98,27 @@ func TestNewRouter(t *testing.T) {
 			t.Fatal("expected router to be created")
 		}
 	})
+
+	t.Run("with nil logger", func(t *testing.T) {
+		config := &ServerConfig{
+			AccessLog: &middlewares.AccessLogConfig{Enabled: true},
+			CORS: &CORSConfig{
+				AllowedOrigins: []string{"http://localhost:3000"},
+			},
+		}
+
+		router := NewRouter(config, nil)
+		router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
 }
 
 func TestNewRouterMiddlewares(t *testing.T) {
@@ -208,3 +234,134 @@ func TestListenAndServe(t *testing.T) {
 		}
 	})
 }
+
+func TestListenAndServeGracefulShutdown(t *testing.T) {
+	t.Run("health check fails during the pre-stop delay", func(t *testing.T) {
+		port := getFreePort(t)
+		config := &ServerConfig{
+			Port:         port,
+			PreStopDelay: goutils.Duration(500 * time.Millisecond),
+		}
+		router := NewRouter(config, slog.Default())
+		router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+
+		go func() {
+			errCh <- ListenAndServe(ctx, router, config)
+		}()
+
+		baseURL := "http://127.0.0.1:" + strconv.Itoa(port)
+		waitForStatus(t, baseURL+"/healthz", http.StatusOK)
+		waitForStatus(t, baseURL+"/readyz", http.StatusOK)
+
+		cancel()
+
+		waitForStatus(t, baseURL+"/healthz", http.StatusServiceUnavailable)
+		waitForStatus(t, baseURL+"/readyz", http.StatusServiceUnavailable)
+		waitForStatus(t, baseURL+"/livez", http.StatusOK)
+		waitForStatus(t, baseURL+"/test", http.StatusOK)
+
+		select {
+		case err := <-errCh:
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("server did not shut down")
+		}
+	})
+
+	t.Run("force close connections after the shutdown timeout", func(t *testing.T) {
+		port := getFreePort(t)
+		config := &ServerConfig{
+			Port:            port,
+			ShutdownTimeout: goutils.Duration(200 * time.Millisecond),
+		}
+
+		handlerStarted := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+
+		router := NewRouter(config, slog.Default())
+		router.Get("/hang", func(w http.ResponseWriter, r *http.Request) {
+			close(handlerStarted)
+			<-release
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+
+		go func() {
+			errCh <- ListenAndServe(ctx, router, config)
+		}()
+
+		baseURL := "http://127.0.0.1:" + strconv.Itoa(port)
+		waitForStatus(t, baseURL+"/healthz", http.StatusOK)
+
+		go func() {
+			resp, err := testHTTPClient.Get(baseURL + "/hang")
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+
+		<-handlerStarted
+		cancel()
+
+		select {
+		case err := <-errCh:
+			if !errors.Is(err, errShutdownTimeout) {
+				t.Fatalf("expected errShutdownTimeout, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "forcibly closed 1 connection(s)") {
+				t.Errorf("expected the number of closed connections in the error, got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("server did not shut down")
+		}
+	})
+}
+
+func getFreePort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// testHTTPClient disables keep-alives so idle connections do not affect connection counts of the server.
+var testHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		DisableKeepAlives: true,
+	},
+}
+
+func waitForStatus(t *testing.T, url string, expected int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for time.Now().Before(deadline) {
+		resp, err := testHTTPClient.Get(url)
+		if err == nil {
+			resp.Body.Close()
+
+			if resp.StatusCode == expected {
+				return
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	t.Fatalf("%s did not respond with status %d", url, expected)
+}
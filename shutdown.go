@@ -0,0 +1,48 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// shutdownServer drains the HTTP server gracefully until the context is done.
+// Remaining connections are forcibly closed once the deadline passes.
+func shutdownServer(ctx context.Context, server *http.Server, tracker *connTracker) error {
+	err := server.Shutdown(ctx)
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+
+	remaining := tracker.Count()
+
+	slog.Warn(
+		"graceful shutdown timed out, forcibly closing connections",
+		slog.Int("connections", remaining),
+	)
+
+	timeoutErr := fmt.Errorf("%w: forcibly closed %d connection(s)", errShutdownTimeout, remaining)
+
+	closeErr := server.Close()
+	if closeErr != nil {
+		return errors.Join(timeoutErr, closeErr)
+	}
+
+	return timeoutErr
+}
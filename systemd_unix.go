@@ -0,0 +1,140 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package gohttps
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const (
+	envListenPID     = "LISTEN_PID"
+	envListenFDs     = "LISTEN_FDS"
+	envListenFDNames = "LISTEN_FDNAMES"
+
+	// The first file descriptor passed by the socket activation protocol. 0, 1 and 2 are stdin, stdout and stderr.
+	listenFDsStart = 3
+)
+
+var errSocketActivationInvalidFDs = errors.New("invalid " + envListenFDs + " environment variable")
+
+// inheritSystemdListeners returns listeners and packet conns passed by the systemd socket activation protocol.
+// Environment variables are unset so they are not inherited by child processes.
+// See https://www.freedesktop.org/software/systemd/man/latest/sd_listen_fds.html.
+func inheritSystemdListeners() ([]namedListener, []namedPacketConn, error) {
+	rawFDs := os.Getenv(envListenFDs)
+	if rawFDs == "" {
+		return nil, nil, nil
+	}
+
+	rawPID := os.Getenv(envListenPID)
+	rawNames := os.Getenv(envListenFDNames)
+
+	unsetEnvs(envListenPID, envListenFDs, envListenFDNames)
+
+	// The file descriptors are intended for another process.
+	if rawPID != strconv.Itoa(os.Getpid()) {
+		return nil, nil, nil
+	}
+
+	return listenersFromFDs(rawFDs, rawNames)
+}
+
+// listenersFromFDs creates listeners from the file descriptors inherited from the parent process.
+// Datagram sockets, e.g. the UDP socket of HTTP/3, are returned as packet conns.
+func listenersFromFDs(rawFDs string, rawNames string) ([]namedListener, []namedPacketConn, error) {
+	count, err := strconv.Atoi(rawFDs)
+	if err != nil || count < 0 {
+		return nil, nil, fmt.Errorf("%w: %s", errSocketActivationInvalidFDs, rawFDs)
+	}
+
+	var names []string
+
+	if rawNames != "" {
+		names = strings.Split(rawNames, ":")
+	}
+
+	results := make([]namedListener, 0, count)
+
+	var packetConns []namedPacketConn
+
+	for i := range count {
+		fd := listenFDsStart + i
+		name := "LISTEN_FD_" + strconv.Itoa(fd)
+
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+
+		syscall.CloseOnExec(fd)
+
+		socketType, err := syscall.GetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_TYPE)
+		if err != nil {
+			socketType = syscall.SOCK_STREAM
+		}
+
+		file := os.NewFile(uintptr(fd), name)
+
+		var (
+			listener   net.Listener
+			packetConn net.PacketConn
+		)
+
+		// FileListener and FilePacketConn duplicate the file descriptor.
+		if socketType == syscall.SOCK_DGRAM {
+			packetConn, err = net.FilePacketConn(file)
+		} else {
+			listener, err = net.FileListener(file)
+		}
+
+		_ = file.Close()
+
+		if err != nil {
+			closeNamedListeners(results)
+			closeNamedPacketConns(packetConns)
+
+			return nil, nil, fmt.Errorf("failed to inherit the listener %s: %w", name, err)
+		}
+
+		if packetConn != nil {
+			packetConns = append(packetConns, namedPacketConn{
+				name: name,
+				conn: packetConn,
+			})
+
+			continue
+		}
+
+		results = append(results, namedListener{
+			name:     name,
+			listener: listener,
+		})
+	}
+
+	return results, packetConns, nil
+}
+
+func unsetEnvs(names ...string) {
+	for _, name := range names {
+		_ = os.Unsetenv(name)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package gohttps
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const envTestSocketActivationChild = "GOHTTPS_TEST_SOCKET_ACTIVATION_CHILD"
+
+func TestSocketActivation(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	file, err := listener.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatalf("failed to get the listener file: %v", err)
+	}
+
+	address := listener.Addr().String()
+	output := new(bytes.Buffer)
+
+	// LISTEN_PID must be the PID of the child process. exec keeps the PID of the shell.
+	cmd := exec.Command(
+		"/bin/sh", "-c", `LISTEN_PID=$$ exec "$0" "$@"`,
+		os.Args[0], "-test.run=^TestSocketActivationChild$", "-test.v",
+	)
+	cmd.Env = append(
+		os.Environ(),
+		envTestSocketActivationChild+"="+address,
+		envListenFDs+"=1",
+		envListenFDNames+"=http",
+	)
+	cmd.ExtraFiles = []*os.File{file}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start the child process: %v", err)
+	}
+
+	// The child process owns the socket from now on.
+	_ = file.Close()
+	_ = listener.Close()
+
+	resp, err := http.Get("http://" + address + "/pid")
+	if err != nil {
+		_ = cmd.Process.Kill()
+
+		t.Fatalf("failed to request the child process: %v\n%s", err, output.String())
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != strconv.Itoa(cmd.Process.Pid) {
+		t.Errorf("expected the response from the child process %d, got %s", cmd.Process.Pid, body)
+	}
+
+	resp, err = http.Get("http://" + address + "/quit")
+	if err == nil {
+		resp.Body.Close()
+	}
+
+	waitErr := make(chan error, 1)
+
+	go func() {
+		waitErr <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-waitErr:
+		if err != nil {
+			t.Errorf("the child process failed: %v\n%s", err, output.String())
+		}
+	case <-time.After(10 * time.Second):
+		_ = cmd.Process.Kill()
+
+		t.Fatalf("the child process did not exit\n%s", output.String())
+	}
+}
+
+// TestSocketActivationChild serves requests on the listener inherited from TestSocketActivation.
+func TestSocketActivationChild(t *testing.T) {
+	inheritedAddress := os.Getenv(envTestSocketActivationChild)
+	if inheritedAddress == "" {
+		t.Skip("run by TestSocketActivation")
+	}
+
+	var quitOnce sync.Once
+
+	quit := make(chan struct{})
+	config := &ServerConfig{
+		Addresses: []ListenAddress{
+			{Name: "http", Address: "127.0.0.1:0"},
+		},
+	}
+
+	router := NewRouter(config, slog.Default())
+	router.Get("/pid", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strconv.Itoa(os.Getpid())))
+	})
+	router.Get("/quit", func(w http.ResponseWriter, r *http.Request) {
+		quitOnce.Do(func() {
+			close(quit)
+		})
+	})
+
+	server, err := NewServer(config, router)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+
+	if _, ok := os.LookupEnv(envListenFDs); ok {
+		t.Error("expected socket activation environment variables to be unset")
+	}
+
+	if addrs := server.Addrs(); len(addrs) != 1 || addrs[0].String() != inheritedAddress {
+		t.Errorf("expected the inherited listener %s, got %v", inheritedAddress, addrs)
+	}
+
+	select {
+	case <-quit:
+	case <-time.After(10 * time.Second):
+		t.Error("timed out waiting for the quit request")
+	}
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Errorf("failed to shutdown server: %v", err)
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"cmp"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/relychan/goutils"
+)
+
+var (
+	errTLSCertificateRequired       = errors.New("tls certificate and key files are required")
+	errTLSClientAuthInvalid         = errors.New("invalid tls client auth type")
+	errTLSClientCAFileRequired      = errors.New("client CA file is required to verify client certificates")
+	errTLSClientCAInvalid           = errors.New("failed to parse client CA certificates")
+	errTLSAllowlistRequiresVerify   = errors.New("allowed client SANs and SPIFFE IDs require client certificate verification")
+	errTLSSPIFFEIDInvalid           = errors.New("invalid SPIFFE ID")
+	errTLSClientCertificateRejected = errors.New("client certificate is not allowed")
+)
+
+const spiffeScheme = "spiffe://"
+
+// TLSClientAuthType represents the policy of the server for TLS client authentication.
+type TLSClientAuthType string
+
+const (
+	// TLSClientAuthNone does not request client certificates.
+	TLSClientAuthNone TLSClientAuthType = "none"
+	// TLSClientAuthRequest requests client certificates but does not require or verify them.
+	TLSClientAuthRequest TLSClientAuthType = "request"
+	// TLSClientAuthRequire requires clients to send certificates but does not verify them.
+	TLSClientAuthRequire TLSClientAuthType = "require"
+	// TLSClientAuthVerifyIfGiven verifies client certificates against the client CA if clients send them.
+	TLSClientAuthVerifyIfGiven TLSClientAuthType = "verify-if-given"
+	// TLSClientAuthRequireAndVerify requires clients to send certificates that are signed by the client CA.
+	TLSClientAuthRequireAndVerify TLSClientAuthType = "require-and-verify"
+)
+
+var tlsClientAuthTypes = map[TLSClientAuthType]tls.ClientAuthType{
+	TLSClientAuthNone:             tls.NoClientCert,
+	TLSClientAuthRequest:          tls.RequestClientCert,
+	TLSClientAuthRequire:          tls.RequireAnyClientCert,
+	TLSClientAuthVerifyIfGiven:    tls.VerifyClientCertIfGiven,
+	TLSClientAuthRequireAndVerify: tls.RequireAndVerifyClientCert,
+}
+
+// TLSConfig represents the TLS configuration of the server.
+type TLSConfig struct {
+	// The minimum TLS version that is acceptable. Default is 1.2.
+	MinVersion string `env:"SERVER_TLS_MIN_VERSION" json:"minVersion,omitempty" yaml:"minVersion,omitempty" jsonschema:"enum=1.0,enum=1.1,enum=1.2,enum=1.3"`
+	// The maximum TLS version that is acceptable. Default is the maximum version supported by Go, currently 1.3.
+	MaxVersion string `env:"SERVER_TLS_MAX_VERSION" json:"maxVersion,omitempty" yaml:"maxVersion,omitempty" jsonschema:"enum=1.0,enum=1.1,enum=1.2,enum=1.3"`
+	// List of enabled TLS 1.0-1.2 cipher suites in IANA names, e.g. TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256.
+	// TLS 1.3 cipher suites are not configurable. Default is the secure cipher suites of Go.
+	CipherSuites []string `env:"SERVER_TLS_CIPHER_SUITES" json:"cipherSuites,omitempty" yaml:"cipherSuites,omitempty"`
+	// The elliptic curves and hybrid post-quantum key exchanges that are used in an ECDHE handshake, in preference order.
+	// Hybrid post-quantum key exchanges are only used by TLS 1.3, so a classic curve is required
+	// unless the minimum version is 1.3. Default is the preference of Go.
+	CurvePreferences []string `env:"SERVER_TLS_CURVE_PREFERENCES" json:"curvePreferences,omitempty" yaml:"curvePreferences,omitempty" jsonschema:"enum=X25519MLKEM768,enum=SecP256r1MLKEM768,enum=SecP384r1MLKEM1024,enum=X25519,enum=P-256,enum=P-384,enum=P-521"`
+	// List of supported application level protocols for the ALPN negotiation in preference order, e.g. h2 and http/1.1.
+	// Protocols that the HTTP server supports are appended if missing.
+	ALPNProtocols []string `env:"SERVER_TLS_ALPN_PROTOCOLS" json:"alpnProtocols,omitempty" yaml:"alpnProtocols,omitempty"`
+	// List of certificate and key pairs to serve. The certificate is selected by the server name indication (SNI)
+	// of the handshake, matching DNS names of certificates including wildcard names, e.g. *.example.com.
+	// The TLS certificate and key files of the server are the default certificate if set, otherwise the first one.
+	Certificates []TLSCertificateConfig `json:"certificates,omitempty" yaml:"certificates,omitempty"`
+	// The directory of certificate and key pairs to serve in addition to the certificates, e.g. example.com.crt and example.com.key.
+	// Pairs are added in lexical order of the file names, and new files are picked up when the certificates are reloaded.
+	CertificateDir string `env:"SERVER_TLS_CERTIFICATE_DIR" json:"certificateDir,omitempty" yaml:"certificateDir,omitempty"`
+	// The interval to poll the certificate and key files for changes. The new key pair is served without restarting.
+	// Default is 1 minute. Set a negative value to disable.
+	ReloadInterval goutils.Duration `env:"SERVER_TLS_RELOAD_INTERVAL" json:"reloadInterval,omitempty" yaml:"reloadInterval,omitempty"`
+	// The durations before the expiry of a served certificate to log a warning at. Each threshold is logged once per certificate.
+	// Default is 30 days, 7 days and 1 day. Set a negative value to disable warnings.
+	ExpiryWarningThresholds []goutils.Duration `json:"expiryWarningThresholds,omitempty" yaml:"expiryWarningThresholds,omitempty"`
+	// Fail startup if a certificate is already expired. Certificates whose private key does not match are always rejected.
+	FailOnExpiredCertificates bool `env:"SERVER_TLS_FAIL_ON_EXPIRED_CERTIFICATES" json:"failOnExpiredCertificates,omitempty" yaml:"failOnExpiredCertificates,omitempty"`
+	// The file of session ticket keys that is shared by replicas, so TLS sessions resume across instances.
+	// Each line is a base64-encoded 32-byte key, e.g. generated by openssl rand -base64 32. The first key encrypts
+	// new tickets and all keys decrypt tickets. The file is reloaded at the reload interval when it changes.
+	SessionTicketKeyFile string `env:"SERVER_TLS_SESSION_TICKET_KEY_FILE" json:"sessionTicketKeyFile,omitempty" yaml:"sessionTicketKeyFile,omitempty"`
+	// The interval to rotate the session ticket key that encrypts new tickets. With the key file, the key advances
+	// through the keys of the file at the same time on every replica. Otherwise, keys are generated in memory.
+	// If both are empty, Go generates and rotates keys automatically.
+	SessionTicketKeyRotationInterval goutils.Duration `env:"SERVER_TLS_SESSION_TICKET_KEY_ROTATION_INTERVAL" json:"sessionTicketKeyRotationInterval,omitempty" yaml:"sessionTicketKeyRotationInterval,omitempty"`
+	// The PEM-encoded CA certificates file to verify client certificates for mutual TLS.
+	ClientCAFile string `env:"SERVER_TLS_CLIENT_CA_FILE" json:"clientCaFile,omitempty" yaml:"clientCaFile,omitempty"`
+	// The policy for TLS client authentication.
+	// Default is require-and-verify if the client CA file is set, otherwise none.
+	ClientAuth TLSClientAuthType `env:"SERVER_TLS_CLIENT_AUTH" json:"clientAuth,omitempty" yaml:"clientAuth,omitempty" jsonschema:"enum=none,enum=request,enum=require,enum=verify-if-given,enum=require-and-verify"`
+	// List of subject alternative names that verified client certificates must contain at least one of.
+	// Match DNS names, IP addresses, email addresses and URIs. DNS names support a leading wildcard label, e.g. *.example.com.
+	AllowedClientSANs []string `env:"SERVER_TLS_ALLOWED_CLIENT_SANS" json:"allowedClientSans,omitempty" yaml:"allowedClientSans,omitempty"`
+	// List of SPIFFE IDs that verified client certificates must contain at least one of, e.g. spiffe://example.org/service.
+	// A trailing /* matches all IDs under the path, e.g. spiffe://example.org/*.
+	AllowedClientSPIFFEIDs []string `env:"SERVER_TLS_ALLOWED_CLIENT_SPIFFE_IDS" json:"allowedClientSpiffeIds,omitempty" yaml:"allowedClientSpiffeIds,omitempty"`
+}
+
+// Validate checks if the configuration is valid.
+func (tc TLSConfig) Validate() error {
+	clientAuth := tc.GetClientAuth()
+
+	if _, ok := tlsClientAuthTypes[clientAuth]; !ok {
+		return fmt.Errorf("%w: %s", errTLSClientAuthInvalid, clientAuth)
+	}
+
+	verifyClientCert := tc.IsClientCertVerified()
+
+	if verifyClientCert && tc.ClientCAFile == "" {
+		return errTLSClientCAFileRequired
+	}
+
+	if !verifyClientCert && (len(tc.AllowedClientSANs) > 0 || len(tc.AllowedClientSPIFFEIDs) > 0) {
+		return errTLSAllowlistRequiresVerify
+	}
+
+	for i, certificate := range tc.Certificates {
+		if certificate.CertFile == "" || certificate.KeyFile == "" {
+			return fmt.Errorf("%w: certificates[%d]", errTLSCertificateRequired, i)
+		}
+	}
+
+	for _, id := range tc.AllowedClientSPIFFEIDs {
+		if !strings.HasPrefix(id, spiffeScheme) || len(id) == len(spiffeScheme) {
+			return fmt.Errorf("%w: %s", errTLSSPIFFEIDInvalid, id)
+		}
+	}
+
+	return tc.applyPolicy(&tls.Config{})
+}
+
+// GetReloadInterval returns the interval to poll the certificate and key files for changes.
+// Default is 1 minute. Returns zero if disabled.
+func (tc TLSConfig) GetReloadInterval() time.Duration {
+	switch {
+	case tc.ReloadInterval > 0:
+		return time.Duration(tc.ReloadInterval)
+	case tc.ReloadInterval < 0:
+		return 0
+	default:
+		return defaultTLSReloadInterval
+	}
+}
+
+// GetExpiryWarningThresholds returns the durations before the expiry of a certificate to log a warning at, in descending order.
+// Default is 30 days, 7 days and 1 day.
+func (tc TLSConfig) GetExpiryWarningThresholds() []time.Duration {
+	if len(tc.ExpiryWarningThresholds) == 0 {
+		return slices.Clone(defaultTLSExpiryWarningThresholds)
+	}
+
+	results := make([]time.Duration, 0, len(tc.ExpiryWarningThresholds))
+
+	for _, threshold := range tc.ExpiryWarningThresholds {
+		if threshold < 0 {
+			return nil
+		}
+
+		if threshold > 0 {
+			results = append(results, time.Duration(threshold))
+		}
+	}
+
+	slices.SortFunc(results, func(a, b time.Duration) int {
+		return cmp.Compare(b, a)
+	})
+
+	return slices.Compact(results)
+}
+
+// GetClientAuth returns the policy for TLS client authentication.
+// Default is require-and-verify if the client CA file is set, otherwise none.
+func (tc TLSConfig) GetClientAuth() TLSClientAuthType {
+	if tc.ClientAuth != "" {
+		return tc.ClientAuth
+	}
+
+	if tc.ClientCAFile != "" {
+		return TLSClientAuthRequireAndVerify
+	}
+
+	return TLSClientAuthNone
+}
+
+// IsClientCertVerified checks if client certificates are verified against the client CA.
+func (tc TLSConfig) IsClientCertVerified() bool {
+	clientAuth := tc.GetClientAuth()
+
+	return clientAuth == TLSClientAuthVerifyIfGiven || clientAuth == TLSClientAuthRequireAndVerify
+}
+
+// hasTLSCertificates checks if any certificate file of the server is configured.
+func hasTLSCertificates(config *ServerConfig) bool {
+	return config.TLSCertFile != "" || config.TLSKeyFile != "" ||
+		(config.TLS != nil && (len(config.TLS.Certificates) > 0 || config.TLS.CertificateDir != ""))
+}
+
+// newTLSConfig creates the TLS config of the HTTP server. Returns nil if TLS is disabled.
+func newTLSConfig(config *ServerConfig) (*tls.Config, error) {
+	devTLS := useDevTLS(config)
+
+	if config.TLS == nil && !devTLS && !hasTLSCertificates(config) {
+		return nil, nil
+	}
+
+	var tlsConfig TLSConfig
+
+	if config.TLS != nil {
+		tlsConfig = *config.TLS
+	}
+
+	err := tlsConfig.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	hasDefaultCertificate := config.TLSCertFile != "" || config.TLSKeyFile != ""
+
+	if (hasDefaultCertificate && (config.TLSCertFile == "" || config.TLSKeyFile == "")) ||
+		(!devTLS && !hasTLSCertificates(config)) {
+		return nil, errTLSCertificateRequired
+	}
+
+	result := &tls.Config{
+		ClientAuth: tlsClientAuthTypes[tlsConfig.GetClientAuth()],
+	}
+
+	err = tlsConfig.applyPolicy(result)
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig.ClientCAFile != "" {
+		result.ClientCAs, err = loadCertPool(tlsConfig.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(tlsConfig.AllowedClientSANs) > 0 || len(tlsConfig.AllowedClientSPIFFEIDs) > 0 {
+		result.VerifyConnection = tlsConfig.verifyClientIdentity
+	}
+
+	protocols, err := newHTTPProtocols(config.Protocols)
+	if err != nil {
+		return nil, err
+	}
+
+	// Protocols that the HTTP server accepts are negotiated if they are not configured.
+	result.NextProtos = adjustNextProtos(result.NextProtos, protocols)
+
+	return result, nil
+}
+
+// verifyClientIdentity checks if the verified client certificate matches the allowlists.
+func (tc TLSConfig) verifyClientIdentity(state tls.ConnectionState) error {
+	// No certificate is sent with the verify-if-given policy.
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	cert := state.VerifiedChains[0][0]
+
+	if slices.ContainsFunc(tc.AllowedClientSANs, func(san string) bool {
+		return matchCertificateSAN(cert, san)
+	}) {
+		return nil
+	}
+
+	if slices.ContainsFunc(tc.AllowedClientSPIFFEIDs, func(id string) bool {
+		return matchCertificateSPIFFEID(cert, id)
+	}) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", errTLSClientCertificateRejected, cert.Subject)
+}
+
+func matchCertificateSAN(cert *x509.Certificate, san string) bool {
+	for _, dnsName := range cert.DNSNames {
+		if matchDNSName(san, dnsName) {
+			return true
+		}
+	}
+
+	for _, ip := range cert.IPAddresses {
+		if ip.String() == san {
+			return true
+		}
+	}
+
+	for _, uri := range cert.URIs {
+		if uri.String() == san {
+			return true
+		}
+	}
+
+	return slices.Contains(cert.EmailAddresses, san)
+}
+
+// matchDNSName checks if the name matches the pattern. The wildcard label only matches a single label.
+func matchDNSName(pattern string, name string) bool {
+	pattern = strings.ToLower(strings.TrimSuffix(pattern, "."))
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+
+	suffix, ok := strings.CutPrefix(pattern, "*.")
+	if !ok {
+		return pattern == name
+	}
+
+	label, rest, found := strings.Cut(name, ".")
+
+	return found && label != "" && rest == suffix
+}
+
+func matchCertificateSPIFFEID(cert *x509.Certificate, id string) bool {
+	prefix, isPrefix := strings.CutSuffix(id, "/*")
+
+	for _, uri := range cert.URIs {
+		if uri.Scheme != "spiffe" {
+			continue
+		}
+
+		value := uri.String()
+
+		if value == id || (isPrefix && strings.HasPrefix(value, prefix+"/")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	rawCerts, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+
+	if !pool.AppendCertsFromPEM(rawCerts) {
+		return nil, fmt.Errorf("%w: %s", errTLSClientCAInvalid, path)
+	}
+
+	return pool, nil
+}
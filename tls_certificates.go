@@ -0,0 +1,127 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"crypto/tls"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	certificateFileExt = ".crt"
+	keyFileExt         = ".key"
+)
+
+var errTLSCertificateNotFound = errors.New("no tls certificate is found")
+
+// TLSCertificateConfig represents a pair of PEM-encoded certificate and key files.
+type TLSCertificateConfig struct {
+	// The PEM-encoded certificate file. The file may contain intermediate certificates after the leaf certificate.
+	CertFile string `json:"certFile" yaml:"certFile"`
+	// The PEM-encoded private key file.
+	KeyFile string `json:"keyFile" yaml:"keyFile"`
+}
+
+// certificateStore selects the key pair to serve by the server name indication (SNI) of the TLS handshake.
+type certificateStore struct {
+	// The first certificate is the default one if no certificate matches the server name.
+	certificates []*tls.Certificate
+	names        map[string]*tls.Certificate
+}
+
+// newCertificateStore indexes certificates by DNS names of their leaf certificates.
+// The certificate that is configured first wins if many certificates have the same name.
+func newCertificateStore(certificates []*tls.Certificate) *certificateStore {
+	store := &certificateStore{
+		certificates: certificates,
+		names:        map[string]*tls.Certificate{},
+	}
+
+	for _, cert := range certificates {
+		for _, name := range cert.Leaf.DNSNames {
+			name = strings.ToLower(name)
+
+			if _, ok := store.names[name]; !ok {
+				store.names[name] = cert
+			}
+		}
+	}
+
+	return store
+}
+
+// getCertificate returns the certificate of the exact server name, then of the wildcard name, e.g. *.example.com,
+// then the default certificate.
+func (cs *certificateStore) getCertificate(serverName string) *tls.Certificate {
+	name := strings.ToLower(strings.TrimSuffix(serverName, "."))
+
+	if name != "" {
+		if cert, ok := cs.names[name]; ok {
+			return cert
+		}
+
+		// A wildcard only matches the left-most label.
+		if _, parent, found := strings.Cut(name, "."); found && parent != "" {
+			if cert, ok := cs.names["*."+parent]; ok {
+				return cert
+			}
+		}
+	}
+
+	return cs.certificates[0]
+}
+
+// listCertificateDir finds key pairs in the directory, e.g. example.com.crt and example.com.key, in lexical order.
+// The layout of Kubernetes TLS secrets, tls.crt and tls.key, is also supported.
+// Certificates without a key file, e.g. ca.crt of secrets that cert-manager creates, are skipped.
+func listCertificateDir(dir string) ([]TLSCertificateConfig, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]bool, len(entries))
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files[entry.Name()] = true
+		}
+	}
+
+	var results []TLSCertificateConfig
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != certificateFileExt {
+			continue
+		}
+
+		keyName := strings.TrimSuffix(name, certificateFileExt) + keyFileExt
+		if !files[keyName] {
+			continue
+		}
+
+		results = append(results, TLSCertificateConfig{
+			CertFile: filepath.Join(dir, name),
+			KeyFile:  filepath.Join(dir, keyName),
+		})
+	}
+
+	return results, nil
+}
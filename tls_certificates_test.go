@@ -0,0 +1,230 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCertificateStore(t *testing.T) {
+	ca := newTestCertificateAuthority(t)
+	defaultCert := ca.issue(t, "default", nil)
+	exactCert := ca.issue(t, "api.example.com", nil)
+	wildcardCert := ca.issue(t, "wildcard", &x509.Certificate{DNSNames: []string{"*.example.com"}})
+	duplicatedCert := ca.issue(t, "api.example.com", nil)
+
+	store := newCertificateStore([]*tls.Certificate{&defaultCert, &exactCert, &wildcardCert, &duplicatedCert})
+
+	tests := []struct {
+		serverName string
+		expected   *tls.Certificate
+	}{
+		{serverName: "api.example.com", expected: &exactCert},
+		{serverName: "API.Example.com.", expected: &exactCert},
+		{serverName: "www.example.com", expected: &wildcardCert},
+		{serverName: "a.b.example.com", expected: &defaultCert},
+		{serverName: "example.com", expected: &defaultCert},
+		{serverName: "example.org", expected: &defaultCert},
+		{serverName: "", expected: &defaultCert},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.serverName, func(t *testing.T) {
+			cert := store.getCertificate(tc.serverName)
+			if cert != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected.Leaf.Subject, cert.Leaf.Subject)
+			}
+		})
+	}
+}
+
+func TestListCertificateDir(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"tls.crt", "tls.key", "example.com.crt", "example.com.key", "ca.pem", ".hidden.crt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "nested.crt"), 0o700); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	keyPairs, err := listCertificateDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []TLSCertificateConfig{
+		{CertFile: filepath.Join(dir, "example.com.crt"), KeyFile: filepath.Join(dir, "example.com.key")},
+		{CertFile: filepath.Join(dir, "tls.crt"), KeyFile: filepath.Join(dir, "tls.key")},
+	}
+
+	if len(keyPairs) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, keyPairs)
+	}
+
+	for i, keyPair := range keyPairs {
+		if keyPair != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], keyPair)
+		}
+	}
+}
+
+func TestListCertificateDirKubernetesSecret(t *testing.T) {
+	dir := t.TempDir()
+
+	// cert-manager mounts the CA certificate next to the key pair, without a key.
+	for _, name := range []string{"ca.crt", "tls.crt", "tls.key"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	keyPairs, err := listCertificateDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := TLSCertificateConfig{CertFile: filepath.Join(dir, "tls.crt"), KeyFile: filepath.Join(dir, "tls.key")}
+
+	if len(keyPairs) != 1 || keyPairs[0] != expected {
+		t.Errorf("expected %v, got %v", expected, keyPairs)
+	}
+}
+
+func TestServerSNICertificates(t *testing.T) {
+	ca := newTestCertificateAuthority(t)
+	defaultCertFile, defaultKeyFile := ca.writeCertificate(t, ca.issue(t, "localhost", nil))
+	apiCertFile, apiKeyFile := ca.writeCertificate(t, ca.issue(t, "api.example.com", nil))
+
+	certDir := t.TempDir()
+	writeTestKeyPair(
+		t,
+		ca.issue(t, "apps", &x509.Certificate{DNSNames: []string{"*.apps.example.com"}}),
+		filepath.Join(certDir, "tls.crt"),
+		filepath.Join(certDir, "tls.key"),
+	)
+
+	// The CA certificate of cert-manager secrets has no key and is skipped.
+	caCert, err := os.ReadFile(defaultCertFile)
+	if err != nil {
+		t.Fatalf("failed to read certificate: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(certDir, "ca.crt"), caCert, 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	config := &ServerConfig{
+		TLSCertFile: defaultCertFile,
+		TLSKeyFile:  defaultKeyFile,
+		TLS: &TLSConfig{
+			Certificates:   []TLSCertificateConfig{{CertFile: apiCertFile, KeyFile: apiKeyFile}},
+			CertificateDir: certDir,
+		},
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	server, err := NewServer(config, NewRouter(config, slog.Default()), WithListener(listener))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = server.Shutdown(context.Background())
+	})
+
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+
+	handshake := func(t *testing.T, serverName string) string {
+		t.Helper()
+
+		conn, err := tls.Dial("tcp", server.Addr().String(), &tls.Config{
+			RootCAs:    ca.pool,
+			ServerName: serverName,
+		})
+		if err != nil {
+			t.Fatalf("failed to handshake with %s: %v", serverName, err)
+		}
+		defer conn.Close()
+
+		return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}
+
+	t.Run("select by server name", func(t *testing.T) {
+		tests := map[string]string{
+			"localhost":             "localhost",
+			"api.example.com":       "api.example.com",
+			"web.apps.example.com":  "apps",
+			"demo.apps.example.com": "apps",
+		}
+
+		for serverName, expected := range tests {
+			if commonName := handshake(t, serverName); commonName != expected {
+				t.Errorf("%s: expected certificate %s, got %s", serverName, expected, commonName)
+			}
+		}
+	})
+
+	t.Run("default certificate", func(t *testing.T) {
+		conn, err := tls.Dial("tcp", server.Addr().String(), &tls.Config{
+			RootCAs:    ca.pool,
+			ServerName: "unknown.example.com",
+		})
+		if err == nil {
+			conn.Close()
+			t.Fatal("expected a certificate verification error")
+		}
+
+		var hostnameErr x509.HostnameError
+		if !errors.As(err, &hostnameErr) || hostnameErr.Certificate.Subject.CommonName != "localhost" {
+			t.Errorf("expected the default certificate, got %v", err)
+		}
+	})
+
+	t.Run("pick up new files in the directory", func(t *testing.T) {
+		writeTestKeyPair(
+			t,
+			ca.issue(t, "shop.example.com", nil),
+			filepath.Join(certDir, "shop.example.com.crt"),
+			filepath.Join(certDir, "shop.example.com.key"),
+		)
+
+		changed, err := server.certReloader.reload()
+		if err != nil || !changed {
+			t.Fatalf("expected the certificates to be reloaded, got %t, %v", changed, err)
+		}
+
+		if commonName := handshake(t, "shop.example.com"); commonName != "shop.example.com" {
+			t.Errorf("expected certificate shop.example.com, got %s", commonName)
+		}
+	})
+}
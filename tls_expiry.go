@@ -0,0 +1,162 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	day = 24 * time.Hour
+
+	defaultTLSExpiryCheckInterval = time.Hour
+)
+
+var defaultTLSExpiryWarningThresholds = []time.Duration{30 * day, 7 * day, day}
+
+// certificateExpiryMonitor exports the expiry time of served certificates and logs warnings when they are about to expire.
+type certificateExpiryMonitor struct {
+	certificates func() []*tls.Certificate
+	thresholds   []time.Duration
+	metrics      *serverMetrics
+	registration metric.Registration
+
+	mu sync.Mutex
+	// The smallest threshold that is logged for each certificate, keyed by the serial number.
+	warned map[string]time.Duration
+}
+
+// newCertificateExpiryMonitor creates a monitor of the certificates in descending order of thresholds.
+func newCertificateExpiryMonitor(
+	certificates func() []*tls.Certificate,
+	thresholds []time.Duration,
+	metrics *serverMetrics,
+) *certificateExpiryMonitor {
+	return &certificateExpiryMonitor{
+		certificates: certificates,
+		thresholds:   thresholds,
+		metrics:      metrics,
+		warned:       map[string]time.Duration{},
+	}
+}
+
+// observe registers the callback of the expiry gauge. The gauge is observed until the monitor stops.
+func (cm *certificateExpiryMonitor) observe() error {
+	registration, err := cm.metrics.meter.RegisterCallback(
+		func(_ context.Context, observer metric.Observer) error {
+			for _, cert := range cm.certificates() {
+				observer.ObserveInt64(
+					cm.metrics.certificateNotAfter,
+					cert.Leaf.NotAfter.Unix(),
+					metric.WithAttributes(
+						attribute.String("subject", cert.Leaf.Subject.CommonName),
+						attribute.String("serial_number", cert.Leaf.SerialNumber.Text(16)),
+					),
+				)
+			}
+
+			return nil
+		},
+		cm.metrics.certificateNotAfter,
+	)
+	if err != nil {
+		return err
+	}
+
+	cm.registration = registration
+
+	return nil
+}
+
+// unregister stops observing the expiry gauge.
+func (cm *certificateExpiryMonitor) unregister() {
+	if cm.registration != nil {
+		_ = cm.registration.Unregister()
+		cm.registration = nil
+	}
+}
+
+// Run checks the expiry of certificates in the interval until the context is done.
+func (cm *certificateExpiryMonitor) Run(ctx context.Context, interval time.Duration) {
+	defer cm.unregister()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cm.check(time.Now())
+		}
+	}
+}
+
+// check logs a warning for each certificate that crosses a threshold, and an error once it is expired.
+func (cm *certificateExpiryMonitor) check(now time.Time) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	for _, cert := range cm.certificates() {
+		serialNumber := cert.Leaf.SerialNumber.Text(16)
+		remaining := cert.Leaf.NotAfter.Sub(now)
+
+		threshold, ok := cm.crossedThreshold(remaining)
+		if !ok {
+			continue
+		}
+
+		if warned, ok := cm.warned[serialNumber]; ok && warned <= threshold {
+			continue
+		}
+
+		cm.warned[serialNumber] = threshold
+
+		attrs := []any{
+			slog.String("subject", cert.Leaf.Subject.CommonName),
+			slog.String("serial_number", serialNumber),
+			slog.Time("not_after", cert.Leaf.NotAfter),
+		}
+
+		if remaining <= 0 {
+			slog.Error("the TLS certificate is expired", attrs...)
+		} else {
+			slog.Warn("the TLS certificate expires soon", append(attrs, slog.Duration("remaining", remaining))...)
+		}
+	}
+}
+
+// crossedThreshold returns the smallest threshold that the remaining duration is within. Zero means expired.
+func (cm *certificateExpiryMonitor) crossedThreshold(remaining time.Duration) (time.Duration, bool) {
+	if remaining <= 0 {
+		return 0, true
+	}
+
+	for i := len(cm.thresholds) - 1; i >= 0; i-- {
+		if remaining <= cm.thresholds[i] {
+			return cm.thresholds[i], true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,207 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/relychan/goutils"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestTLSConfig_GetExpiryWarningThresholds(t *testing.T) {
+	tests := []struct {
+		name       string
+		thresholds []goutils.Duration
+		expected   []time.Duration
+	}{
+		{
+			name:     "default",
+			expected: []time.Duration{30 * day, 7 * day, day},
+		},
+		{
+			name:       "sorted and deduplicated",
+			thresholds: []goutils.Duration{goutils.Duration(day), 0, goutils.Duration(14 * day), goutils.Duration(day)},
+			expected:   []time.Duration{14 * day, day},
+		},
+		{
+			name:       "disabled",
+			thresholds: []goutils.Duration{goutils.Duration(day), -1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := TLSConfig{ExpiryWarningThresholds: tc.thresholds}.GetExpiryWarningThresholds()
+			if !slices.Equal(result, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestCertificateExpiryMonitor(t *testing.T) {
+	ca := newTestCertificateAuthority(t)
+	now := time.Now()
+
+	expiring := ca.issue(t, "expiring", &x509.Certificate{NotAfter: now.Add(5 * day)})
+	expired := ca.issue(t, "expired", &x509.Certificate{NotBefore: now.Add(-2 * day), NotAfter: now.Add(-day)})
+	valid := ca.issue(t, "valid", &x509.Certificate{NotAfter: now.Add(90 * day)})
+	certificates := []*tls.Certificate{&expiring, &expired, &valid}
+
+	reader := sdkmetric.NewManualReader()
+
+	metrics, err := newServerMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatalf("failed to create metrics: %v", err)
+	}
+
+	monitor := newCertificateExpiryMonitor(
+		func() []*tls.Certificate { return certificates },
+		TLSConfig{}.GetExpiryWarningThresholds(),
+		metrics,
+	)
+
+	if err := monitor.observe(); err != nil {
+		t.Fatalf("failed to observe metrics: %v", err)
+	}
+
+	serialNumber := func(cert tls.Certificate) string {
+		return cert.Leaf.SerialNumber.Text(16)
+	}
+
+	t.Run("warn at thresholds", func(t *testing.T) {
+		monitor.check(now)
+
+		expected := map[string]time.Duration{
+			serialNumber(expiring): 7 * day,
+			serialNumber(expired):  0,
+		}
+
+		if len(monitor.warned) != len(expected) {
+			t.Fatalf("expected %v, got %v", expected, monitor.warned)
+		}
+
+		for key, threshold := range expected {
+			if monitor.warned[key] != threshold {
+				t.Errorf("expected threshold %s of %s, got %s", threshold, key, monitor.warned[key])
+			}
+		}
+
+		monitor.check(now.Add(4*day + time.Hour))
+
+		if threshold := monitor.warned[serialNumber(expiring)]; threshold != day {
+			t.Errorf("expected threshold %s, got %s", day, threshold)
+		}
+	})
+
+	t.Run("expiry gauge", func(t *testing.T) {
+		var data metricdata.ResourceMetrics
+
+		if err := reader.Collect(context.Background(), &data); err != nil {
+			t.Fatalf("failed to collect metrics: %v", err)
+		}
+
+		got := map[string]int64{}
+
+		for _, scope := range data.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				if m.Name != "gohttps.tls.certificate.not_after" {
+					continue
+				}
+
+				gauge, ok := m.Data.(metricdata.Gauge[int64])
+				if !ok {
+					t.Fatalf("expected int64 gauge, got %T", m.Data)
+				}
+
+				for _, point := range gauge.DataPoints {
+					subject, _ := point.Attributes.Value("subject")
+					got[subject.AsString()] = point.Value
+				}
+			}
+		}
+
+		for _, cert := range certificates {
+			subject := cert.Leaf.Subject.CommonName
+			if got[subject] != cert.Leaf.NotAfter.Unix() {
+				t.Errorf("expected expiry %d of %s, got %d", cert.Leaf.NotAfter.Unix(), subject, got[subject])
+			}
+		}
+	})
+
+	t.Run("unregister once stopped", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		monitor.Run(ctx, time.Hour)
+
+		var data metricdata.ResourceMetrics
+
+		if err := reader.Collect(context.Background(), &data); err != nil {
+			t.Fatalf("failed to collect metrics: %v", err)
+		}
+
+		for _, scope := range data.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				if gauge, ok := m.Data.(metricdata.Gauge[int64]); ok && len(gauge.DataPoints) > 0 {
+					t.Errorf("expected no data point, got %v", gauge.DataPoints)
+				}
+			}
+		}
+	})
+}
+
+func TestCertificateReloader_FailOnExpiredCertificates(t *testing.T) {
+	ca := newTestCertificateAuthority(t)
+	certFile, keyFile := ca.writeCertificate(t, ca.issue(t, "localhost", &x509.Certificate{
+		NotBefore: time.Now().Add(-2 * day),
+		NotAfter:  time.Now().Add(-day),
+	}))
+
+	metrics, err := newServerMetrics(sdkmetric.NewMeterProvider())
+	if err != nil {
+		t.Fatalf("failed to create metrics: %v", err)
+	}
+
+	config := &ServerConfig{TLSCertFile: certFile, TLSKeyFile: keyFile, TLS: &TLSConfig{}}
+
+	if _, err := newCertificateReloader(config, metrics); err != nil {
+		t.Errorf("expected the expired certificate to be served, got %v", err)
+	}
+
+	config.TLS.FailOnExpiredCertificates = true
+
+	_, err = newCertificateReloader(config, metrics)
+	if !errors.Is(err, errTLSCertificateExpired) {
+		t.Errorf("expected errTLSCertificateExpired, got %v", err)
+	}
+
+	t.Run("mismatched key", func(t *testing.T) {
+		_, otherKeyFile := ca.writeCertificate(t, ca.issue(t, "other", nil))
+
+		_, err := newCertificateReloader(&ServerConfig{TLSCertFile: certFile, TLSKeyFile: otherKeyFile}, metrics)
+		if err == nil {
+			t.Error("expected the mismatched key to be rejected")
+		}
+	})
+}
@@ -0,0 +1,198 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	errTLSVersionInvalid             = errors.New("invalid TLS version")
+	errTLSVersionRangeInvalid        = errors.New("the minimum TLS version must not be greater than the maximum version")
+	errTLSCipherSuiteInvalid         = errors.New("unsupported TLS cipher suite")
+	errTLSCipherSuiteInsecure        = errors.New("insecure TLS cipher suite")
+	errTLSCipherSuitesNotApplicable  = errors.New("cipher suites are not configurable with TLS 1.3 only")
+	errTLSCipherSuiteNotConfigurable = errors.New("TLS 1.3 cipher suites are not configurable")
+	errTLSCipherSuiteVersionMismatch = errors.New("cipher suite does not support any enabled TLS version")
+	errTLSCurveInvalid               = errors.New("unsupported TLS curve")
+	errTLSCurvesRequireTLS13         = errors.New("hybrid post-quantum key exchanges require TLS 1.3")
+	errTLSALPNProtocolEmpty          = errors.New("ALPN protocol must be a non-empty string")
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var tlsCurves = map[string]tls.CurveID{
+	"X25519MLKEM768":     tls.X25519MLKEM768,
+	"SecP256r1MLKEM768":  tls.SecP256r1MLKEM768,
+	"SecP384r1MLKEM1024": tls.SecP384r1MLKEM1024,
+	"X25519":             tls.X25519,
+	"P-256":              tls.CurveP256,
+	"P-384":              tls.CurveP384,
+	"P-521":              tls.CurveP521,
+}
+
+// applyPolicy parses and validates the version, cipher suites, curves and ALPN protocols,
+// then applies them to the TLS config.
+func (tc TLSConfig) applyPolicy(result *tls.Config) error {
+	minVersion, err := parseTLSVersion(tc.MinVersion, tls.VersionTLS12)
+	if err != nil {
+		return err
+	}
+
+	maxVersion, err := parseTLSVersion(tc.MaxVersion, tls.VersionTLS13)
+	if err != nil {
+		return err
+	}
+
+	if minVersion > maxVersion {
+		return fmt.Errorf("%w: %s > %s", errTLSVersionRangeInvalid, tc.MinVersion, tc.MaxVersion)
+	}
+
+	cipherSuites, err := parseTLSCipherSuites(tc.CipherSuites, minVersion, maxVersion)
+	if err != nil {
+		return err
+	}
+
+	curves, err := parseTLSCurves(tc.CurvePreferences, minVersion)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(tc.ALPNProtocols, "") {
+		return errTLSALPNProtocolEmpty
+	}
+
+	result.MinVersion = minVersion
+	result.CipherSuites = cipherSuites
+	result.CurvePreferences = curves
+	result.NextProtos = tc.ALPNProtocols
+
+	// Zero means the maximum version supported by Go.
+	if tc.MaxVersion != "" {
+		result.MaxVersion = maxVersion
+	}
+
+	return nil
+}
+
+func parseTLSVersion(name string, defaultVersion uint16) (uint16, error) {
+	if name == "" {
+		return defaultVersion, nil
+	}
+
+	version, ok := tlsVersions[name]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", errTLSVersionInvalid, name)
+	}
+
+	return version, nil
+}
+
+func parseTLSCipherSuites(names []string, minVersion uint16, maxVersion uint16) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	if minVersion == tls.VersionTLS13 {
+		return nil, errTLSCipherSuitesNotApplicable
+	}
+
+	results := make([]uint16, len(names))
+
+	for i, name := range names {
+		cipherSuite, err := findTLSCipherSuite(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.ContainsFunc(cipherSuite.SupportedVersions, func(version uint16) bool {
+			return version < tls.VersionTLS13
+		}) {
+			return nil, fmt.Errorf("%w: %s", errTLSCipherSuiteNotConfigurable, name)
+		}
+
+		if !slices.ContainsFunc(cipherSuite.SupportedVersions, func(version uint16) bool {
+			return version >= minVersion && version <= maxVersion
+		}) {
+			return nil, fmt.Errorf("%w: %s", errTLSCipherSuiteVersionMismatch, name)
+		}
+
+		results[i] = cipherSuite.ID
+	}
+
+	return results, nil
+}
+
+func findTLSCipherSuite(name string) (*tls.CipherSuite, error) {
+	for _, cipherSuite := range tls.CipherSuites() {
+		if cipherSuite.Name == name {
+			return cipherSuite, nil
+		}
+	}
+
+	for _, cipherSuite := range tls.InsecureCipherSuites() {
+		if cipherSuite.Name == name {
+			return nil, fmt.Errorf("%w: %s", errTLSCipherSuiteInsecure, name)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", errTLSCipherSuiteInvalid, name)
+}
+
+func parseTLSCurves(names []string, minVersion uint16) ([]tls.CurveID, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	results := make([]tls.CurveID, len(names))
+	hasClassicCurve := false
+
+	for i, name := range names {
+		curve, ok := tlsCurves[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errTLSCurveInvalid, name)
+		}
+
+		if !isHybridTLSCurve(curve) {
+			hasClassicCurve = true
+		}
+
+		results[i] = curve
+	}
+
+	// No key exchange would be available for handshakes below TLS 1.3.
+	if minVersion < tls.VersionTLS13 && !hasClassicCurve {
+		return nil, errTLSCurvesRequireTLS13
+	}
+
+	return results, nil
+}
+
+func isHybridTLSCurve(curve tls.CurveID) bool {
+	switch curve {
+	case tls.X25519MLKEM768, tls.SecP256r1MLKEM768, tls.SecP384r1MLKEM1024:
+		return true
+	default:
+		return false
+	}
+}
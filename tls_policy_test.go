@@ -0,0 +1,222 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"slices"
+	"testing"
+)
+
+func TestTLSConfig_ValidatePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  TLSConfig
+		wantErr error
+	}{
+		{
+			name: "valid",
+			config: TLSConfig{
+				MinVersion:       "1.2",
+				MaxVersion:       "1.3",
+				CipherSuites:     []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+				CurvePreferences: []string{"X25519MLKEM768", "X25519", "P-256"},
+				ALPNProtocols:    []string{"h2", "http/1.1"},
+			},
+		},
+		{
+			name:    "invalid version",
+			config:  TLSConfig{MinVersion: "1.4"},
+			wantErr: errTLSVersionInvalid,
+		},
+		{
+			name:    "min version greater than max version",
+			config:  TLSConfig{MinVersion: "1.3", MaxVersion: "1.2"},
+			wantErr: errTLSVersionRangeInvalid,
+		},
+		{
+			name:    "max version lower than the default min version",
+			config:  TLSConfig{MaxVersion: "1.1"},
+			wantErr: errTLSVersionRangeInvalid,
+		},
+		{
+			name:    "unknown cipher suite",
+			config:  TLSConfig{CipherSuites: []string{"TLS_UNKNOWN"}},
+			wantErr: errTLSCipherSuiteInvalid,
+		},
+		{
+			name:    "insecure cipher suite",
+			config:  TLSConfig{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}},
+			wantErr: errTLSCipherSuiteInsecure,
+		},
+		{
+			name:    "cipher suites with TLS 1.3 only",
+			config:  TLSConfig{MinVersion: "1.3", CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}},
+			wantErr: errTLSCipherSuitesNotApplicable,
+		},
+		{
+			name:    "TLS 1.3 cipher suite",
+			config:  TLSConfig{CipherSuites: []string{"TLS_AES_128_GCM_SHA256"}},
+			wantErr: errTLSCipherSuiteNotConfigurable,
+		},
+		{
+			name: "cipher suite of disabled versions",
+			config: TLSConfig{
+				MinVersion:   "1.0",
+				MaxVersion:   "1.1",
+				CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+			},
+			wantErr: errTLSCipherSuiteVersionMismatch,
+		},
+		{
+			name:    "unknown curve",
+			config:  TLSConfig{CurvePreferences: []string{"X448"}},
+			wantErr: errTLSCurveInvalid,
+		},
+		{
+			name:    "hybrid curves only with TLS 1.2",
+			config:  TLSConfig{MaxVersion: "1.2", CurvePreferences: []string{"X25519MLKEM768"}},
+			wantErr: errTLSCurvesRequireTLS13,
+		},
+		{
+			name:    "hybrid curves only with TLS 1.2 and 1.3",
+			config:  TLSConfig{MinVersion: "1.2", MaxVersion: "1.3", CurvePreferences: []string{"X25519MLKEM768", "SecP256r1MLKEM768"}},
+			wantErr: errTLSCurvesRequireTLS13,
+		},
+		{
+			name:    "empty ALPN protocol",
+			config:  TLSConfig{ALPNProtocols: []string{"h2", ""}},
+			wantErr: errTLSALPNProtocolEmpty,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.wantErr == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if !errors.Is(err, tc.wantErr) {
+				t.Errorf("expected %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTLSConfig_applyPolicy(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		var result tls.Config
+
+		if err := (TLSConfig{}).applyPolicy(&result); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.MinVersion != tls.VersionTLS12 || result.MaxVersion != 0 {
+			t.Errorf("unexpected versions: %x - %x", result.MinVersion, result.MaxVersion)
+		}
+
+		if result.CipherSuites != nil || result.CurvePreferences != nil {
+			t.Error("expected the default cipher suites and curves of Go")
+		}
+	})
+
+	t.Run("explicit", func(t *testing.T) {
+		var result tls.Config
+
+		err := TLSConfig{
+			MinVersion:       "1.2",
+			MaxVersion:       "1.2",
+			CipherSuites:     []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+			CurvePreferences: []string{"P-384", "X25519"},
+			ALPNProtocols:    []string{"http/1.1"},
+		}.applyPolicy(&result)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.MinVersion != tls.VersionTLS12 || result.MaxVersion != tls.VersionTLS12 {
+			t.Errorf("unexpected versions: %x - %x", result.MinVersion, result.MaxVersion)
+		}
+
+		if !slices.Equal(result.CipherSuites, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}) {
+			t.Errorf("unexpected cipher suites: %v", result.CipherSuites)
+		}
+
+		if !slices.Equal(result.CurvePreferences, []tls.CurveID{tls.CurveP384, tls.X25519}) {
+			t.Errorf("unexpected curves: %v", result.CurvePreferences)
+		}
+
+		if !slices.Equal(result.NextProtos, []string{"http/1.1"}) {
+			t.Errorf("unexpected ALPN protocols: %v", result.NextProtos)
+		}
+	})
+}
+
+func TestServerTLSPolicy(t *testing.T) {
+	ca := newTestCertificateAuthority(t)
+	certFile, keyFile := ca.writeCertificate(t, ca.issue(t, "localhost", nil))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	server := newTestServer(t, &ServerConfig{
+		TLSCertFile: certFile,
+		TLSKeyFile:  keyFile,
+		TLS: &TLSConfig{
+			MinVersion:       "1.3",
+			CurvePreferences: []string{"X25519MLKEM768"},
+		},
+	}, WithListener(listener))
+
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+
+	dial := func(maxVersion uint16) (tls.ConnectionState, error) {
+		conn, err := tls.Dial("tcp", server.Addr().String(), &tls.Config{
+			RootCAs:    ca.pool,
+			ServerName: "localhost",
+			MaxVersion: maxVersion,
+			NextProtos: []string{"h2", "http/1.1"},
+		})
+		if err != nil {
+			return tls.ConnectionState{}, err
+		}
+		defer conn.Close()
+
+		return conn.ConnectionState(), nil
+	}
+
+	state, err := dial(tls.VersionTLS13)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	if state.CurveID != tls.X25519MLKEM768 {
+		t.Errorf("expected X25519MLKEM768, got %s", state.CurveID)
+	}
+
+	if state.NegotiatedProtocol != "h2" {
+		t.Errorf("expected h2, got %s", state.NegotiatedProtocol)
+	}
+
+	if _, err := dial(tls.VersionTLS12); err == nil {
+		t.Error("expected TLS 1.2 handshakes to fail")
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const defaultTLSReloadInterval = time.Minute
+
+var errTLSCertificateExpired = errors.New("tls certificate is expired")
+
+// certificateReloader serves the key pairs of the certificate files and reloads them when the files change.
+type certificateReloader struct {
+	keyPairs       []TLSCertificateConfig
+	certificateDir string
+	interval       time.Duration
+	rejectExpired  bool
+	metrics        *serverMetrics
+	store          atomic.Pointer[certificateStore]
+	// The checksum of the loaded files, and of the files that failed to load so they are not reported repeatedly.
+	checksum       []byte
+	failedChecksum []byte
+}
+
+// newCertificateReloader creates a reloader of the certificates in the server config and loads them immediately.
+// The certificate and key files of the server config are the default key pair, followed by the certificates
+// of the TLS config and the certificate directory.
+func newCertificateReloader(config *ServerConfig, metrics *serverMetrics) (*certificateReloader, error) {
+	var tlsConfig TLSConfig
+
+	if config.TLS != nil {
+		tlsConfig = *config.TLS
+	}
+
+	reloader := &certificateReloader{
+		certificateDir: tlsConfig.CertificateDir,
+		interval:       tlsConfig.GetReloadInterval(),
+		rejectExpired:  tlsConfig.FailOnExpiredCertificates,
+		metrics:        metrics,
+	}
+
+	if config.TLSCertFile != "" {
+		reloader.keyPairs = append(reloader.keyPairs, TLSCertificateConfig{
+			CertFile: config.TLSCertFile,
+			KeyFile:  config.TLSKeyFile,
+		})
+	}
+
+	reloader.keyPairs = append(reloader.keyPairs, tlsConfig.Certificates...)
+
+	_, err := reloader.reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+// GetCertificate returns the key pair that matches the server name of the handshake.
+// It implements the GetCertificate hook of tls.Config.
+func (cr *certificateReloader) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	var serverName string
+
+	if hello != nil {
+		serverName = hello.ServerName
+	}
+
+	return cr.store.Load().getCertificate(serverName), nil
+}
+
+// Run polls the certificate files in the interval until the context is done.
+func (cr *certificateReloader) Run(ctx context.Context) {
+	if cr.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(cr.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cr.reloadAndRecord(ctx)
+		}
+	}
+}
+
+func (cr *certificateReloader) reloadAndRecord(ctx context.Context) {
+	changed, err := cr.reload()
+	if err != nil {
+		slog.Error(
+			"failed to reload the TLS certificates, keep serving the current certificates",
+			slog.String("error", err.Error()),
+		)
+
+		cr.metrics.certificateReloads.Add(ctx, 1, metric.WithAttributes(attribute.String("result", "failure")))
+
+		return
+	}
+
+	if !changed {
+		return
+	}
+
+	slog.Info(
+		"reloaded the TLS certificates",
+		slog.Int("count", len(cr.store.Load().certificates)),
+	)
+
+	cr.metrics.certificateReloads.Add(ctx, 1, metric.WithAttributes(attribute.String("result", "success")))
+}
+
+// reload loads the key pairs if the content of the files changed since the last reload.
+// The current key pairs are kept if any new one is invalid.
+func (cr *certificateReloader) reload() (bool, error) {
+	keyPairs := cr.keyPairs
+
+	if cr.certificateDir != "" {
+		dirKeyPairs, err := listCertificateDir(cr.certificateDir)
+		if err != nil {
+			return false, err
+		}
+
+		keyPairs = append(slices.Clip(keyPairs), dirKeyPairs...)
+	}
+
+	if len(keyPairs) == 0 {
+		return false, fmt.Errorf("%w: %s", errTLSCertificateNotFound, cr.certificateDir)
+	}
+
+	type keyPairContent struct {
+		certFile string
+		certPEM  []byte
+		keyPEM   []byte
+	}
+
+	contents := make([]keyPairContent, len(keyPairs))
+	hash := sha256.New()
+
+	for i, keyPair := range keyPairs {
+		certPEM, err := os.ReadFile(keyPair.CertFile)
+		if err != nil {
+			return false, err
+		}
+
+		keyPEM, err := os.ReadFile(keyPair.KeyFile)
+		if err != nil {
+			return false, err
+		}
+
+		contents[i] = keyPairContent{
+			certFile: keyPair.CertFile,
+			certPEM:  certPEM,
+			keyPEM:   keyPEM,
+		}
+
+		hash.Write([]byte(keyPair.CertFile))
+		hash.Write(certPEM)
+		hash.Write(keyPEM)
+	}
+
+	checksum := hash.Sum(nil)
+
+	if bytes.Equal(checksum, cr.checksum) || bytes.Equal(checksum, cr.failedChecksum) {
+		return false, nil
+	}
+
+	// A replacement certificate must not be expired, otherwise the current one is served.
+	rejectExpired := cr.rejectExpired || cr.store.Load() != nil
+	certificates := make([]*tls.Certificate, len(contents))
+
+	for i, content := range contents {
+		certificate, err := parseCertificate(content.certPEM, content.keyPEM, rejectExpired)
+		if err != nil {
+			// The files may be read while they are partially written, they are loaded again once the content changes.
+			cr.failedChecksum = checksum
+
+			return false, fmt.Errorf("failed to load the TLS certificate %s: %w", content.certFile, err)
+		}
+
+		certificates[i] = certificate
+	}
+
+	cr.store.Store(newCertificateStore(certificates))
+	cr.checksum = checksum
+	cr.failedChecksum = nil
+
+	return true, nil
+}
+
+// parseCertificate parses the key pair and checks that the private key matches the certificate.
+func parseCertificate(certPEM []byte, keyPEM []byte, rejectExpired bool) (*tls.Certificate, error) {
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	if rejectExpired && time.Now().After(certificate.Leaf.NotAfter) {
+		return nil, fmt.Errorf(
+			"%w: %s",
+			errTLSCertificateExpired,
+			certificate.Leaf.NotAfter.Format(time.RFC3339),
+		)
+	}
+
+	return &certificate, nil
+}
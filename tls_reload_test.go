@@ -0,0 +1,188 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/relychan/goutils"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestCertificateReloader(t *testing.T) {
+	ca := newTestCertificateAuthority(t)
+	certFile, keyFile := ca.writeCertificate(t, ca.issue(t, "localhost", nil))
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	metrics, err := newServerMetrics(meterProvider)
+	if err != nil {
+		t.Fatalf("failed to create metrics: %v", err)
+	}
+
+	reloader, err := newCertificateReloader(&ServerConfig{TLSCertFile: certFile, TLSKeyFile: keyFile}, metrics)
+	if err != nil {
+		t.Fatalf("failed to create reloader: %v", err)
+	}
+
+	currentSerial := func() string {
+		cert, err := reloader.GetCertificate(nil)
+		if err != nil {
+			t.Fatalf("failed to get certificate: %v", err)
+		}
+
+		return cert.Leaf.SerialNumber.String()
+	}
+
+	initialSerial := currentSerial()
+
+	t.Run("unchanged files", func(t *testing.T) {
+		reloader.reloadAndRecord(context.Background())
+
+		if serial := currentSerial(); serial != initialSerial {
+			t.Errorf("expected serial %s, got %s", initialSerial, serial)
+		}
+	})
+
+	t.Run("invalid files keep the current certificate", func(t *testing.T) {
+		if err := os.WriteFile(certFile, []byte("partial"), 0o600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		reloader.reloadAndRecord(context.Background())
+		// The same invalid content is not reported again.
+		reloader.reloadAndRecord(context.Background())
+
+		if serial := currentSerial(); serial != initialSerial {
+			t.Errorf("expected serial %s, got %s", initialSerial, serial)
+		}
+	})
+
+	t.Run("expired replacement", func(t *testing.T) {
+		expired := ca.issue(t, "localhost", &x509.Certificate{
+			NotBefore: time.Now().Add(-2 * time.Hour),
+			NotAfter:  time.Now().Add(-time.Hour),
+		})
+		writeTestKeyPair(t, expired, certFile, keyFile)
+
+		_, err := reloader.reload()
+		if !errors.Is(err, errTLSCertificateExpired) {
+			t.Errorf("expected errTLSCertificateExpired, got %v", err)
+		}
+
+		if serial := currentSerial(); serial != initialSerial {
+			t.Errorf("expected serial %s, got %s", initialSerial, serial)
+		}
+	})
+
+	t.Run("valid replacement", func(t *testing.T) {
+		replacement := ca.issue(t, "localhost", nil)
+		writeTestKeyPair(t, replacement, certFile, keyFile)
+
+		reloader.reloadAndRecord(context.Background())
+
+		if serial := currentSerial(); serial != replacement.Leaf.SerialNumber.String() {
+			t.Errorf("expected serial %s, got %s", replacement.Leaf.SerialNumber, serial)
+		}
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		var data metricdata.ResourceMetrics
+
+		if err := reader.Collect(context.Background(), &data); err != nil {
+			t.Fatalf("failed to collect metrics: %v", err)
+		}
+
+		got := map[string]int64{}
+
+		for _, scope := range data.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				if m.Name != "gohttps.tls.certificate.reloads" {
+					continue
+				}
+
+				sum, ok := m.Data.(metricdata.Sum[int64])
+				if !ok {
+					t.Fatalf("expected int64 sum, got %T", m.Data)
+				}
+
+				for _, point := range sum.DataPoints {
+					result, _ := point.Attributes.Value(attribute.Key("result"))
+					got[result.AsString()] += point.Value
+				}
+			}
+		}
+
+		if got["success"] != 1 || got["failure"] != 1 {
+			t.Errorf("expected 1 success and 1 failure, got %v", got)
+		}
+	})
+}
+
+func TestCertificateReloader_Run(t *testing.T) {
+	ca := newTestCertificateAuthority(t)
+	certFile, keyFile := ca.writeCertificate(t, ca.issue(t, "localhost", nil))
+
+	metrics, err := newServerMetrics(sdkmetric.NewMeterProvider())
+	if err != nil {
+		t.Fatalf("failed to create metrics: %v", err)
+	}
+
+	reloader, err := newCertificateReloader(&ServerConfig{
+		TLSCertFile: certFile,
+		TLSKeyFile:  keyFile,
+		TLS:         &TLSConfig{ReloadInterval: goutils.Duration(10 * time.Millisecond)},
+	}, metrics)
+	if err != nil {
+		t.Fatalf("failed to create reloader: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		reloader.Run(ctx)
+		close(done)
+	}()
+
+	replacement := ca.issue(t, "localhost", nil)
+	writeTestKeyPair(t, replacement, certFile, keyFile)
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for {
+		cert, _ := reloader.GetCertificate(nil)
+		if cert.Leaf.SerialNumber.Cmp(replacement.Leaf.SerialNumber) == 0 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the certificate to be reloaded")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	<-done
+}
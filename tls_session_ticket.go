@@ -0,0 +1,234 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	sessionTicketKeySize = 32
+	// The number of previous keys that are generated in memory to keep decrypting tickets after rotation.
+	maxPreviousSessionTicketKeys = 2
+)
+
+var (
+	errTLSSessionTicketKeyInvalid  = errors.New("session ticket key must be a base64-encoded 32-byte key")
+	errTLSSessionTicketKeyNotFound = errors.New("no session ticket key is found")
+)
+
+// sessionTicketKeyManager sets the session ticket keys of the TLS config and rotates them.
+// Keys are loaded from the key file that is shared by replicas, or generated in memory if the file is not set.
+type sessionTicketKeyManager struct {
+	tlsConfig        *tls.Config
+	keyFile          string
+	reloadInterval   time.Duration
+	rotationInterval time.Duration
+	keys             [][sessionTicketKeySize]byte
+	checksum         []byte
+	// The index of the key that encrypts new tickets, or -1 if keys are not applied yet.
+	current int
+}
+
+// newSessionTicketKeyManager creates a manager and sets the initial keys to the TLS config.
+// Returns nil if neither the key file nor the rotation interval is set, Go then rotates keys automatically.
+func newSessionTicketKeyManager(tlsConfig *tls.Config, config TLSConfig) (*sessionTicketKeyManager, error) {
+	if config.SessionTicketKeyFile == "" && config.SessionTicketKeyRotationInterval <= 0 {
+		return nil, nil
+	}
+
+	manager := &sessionTicketKeyManager{
+		tlsConfig:        tlsConfig,
+		keyFile:          config.SessionTicketKeyFile,
+		reloadInterval:   config.GetReloadInterval(),
+		rotationInterval: time.Duration(max(config.SessionTicketKeyRotationInterval, 0)),
+		current:          -1,
+	}
+
+	if manager.keyFile == "" {
+		err := manager.generate()
+		if err != nil {
+			return nil, err
+		}
+
+		return manager, nil
+	}
+
+	_, err := manager.reload()
+	if err != nil {
+		return nil, err
+	}
+
+	manager.apply(time.Now())
+
+	return manager, nil
+}
+
+// Run reloads the key file and rotates keys until the context is done.
+func (sm *sessionTicketKeyManager) Run(ctx context.Context) {
+	interval := sm.rotationInterval
+
+	if sm.keyFile != "" && sm.reloadInterval > 0 && (interval <= 0 || sm.reloadInterval < interval) {
+		interval = sm.reloadInterval
+	}
+
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			sm.tick(now)
+		}
+	}
+}
+
+func (sm *sessionTicketKeyManager) tick(now time.Time) {
+	if sm.keyFile == "" {
+		err := sm.generate()
+		if err != nil {
+			slog.Error("failed to rotate the TLS session ticket keys", slog.String("error", err.Error()))
+		}
+
+		return
+	}
+
+	changed, err := sm.reload()
+	if err != nil {
+		slog.Error(
+			"failed to reload the TLS session ticket keys, keep using the current keys",
+			slog.String("error", err.Error()),
+		)
+	} else if changed {
+		slog.Info("reloaded the TLS session ticket keys", slog.Int("count", len(sm.keys)))
+	}
+
+	sm.apply(now)
+}
+
+// generate creates a new key in memory that encrypts new tickets. Previous keys still decrypt tickets.
+func (sm *sessionTicketKeyManager) generate() error {
+	var key [sessionTicketKeySize]byte
+
+	_, err := rand.Read(key[:])
+	if err != nil {
+		return err
+	}
+
+	sm.keys = append([][sessionTicketKeySize]byte{key}, sm.keys[:min(len(sm.keys), maxPreviousSessionTicketKeys)]...)
+	sm.tlsConfig.SetSessionTicketKeys(sm.keys)
+
+	return nil
+}
+
+// reload parses the key file if the content changed since the last reload.
+func (sm *sessionTicketKeyManager) reload() (bool, error) {
+	content, err := os.ReadFile(sm.keyFile)
+	if err != nil {
+		return false, err
+	}
+
+	checksum := sha256.Sum256(content)
+
+	if bytes.Equal(checksum[:], sm.checksum) {
+		return false, nil
+	}
+
+	keys, err := parseSessionTicketKeys(content)
+	if err != nil {
+		return false, fmt.Errorf("failed to load the session ticket keys %s: %w", sm.keyFile, err)
+	}
+
+	sm.keys = keys
+	sm.checksum = checksum[:]
+	sm.current = -1
+
+	return true, nil
+}
+
+// apply sets the keys to the TLS config if the key to encrypt new tickets changes.
+// With the rotation interval, the key advances through the keys of the file at the same time on every replica
+// because the index is derived from the wall clock. Otherwise the first key encrypts new tickets.
+func (sm *sessionTicketKeyManager) apply(now time.Time) {
+	var current int
+
+	if sm.rotationInterval > 0 {
+		current = int((now.UnixNano() / int64(sm.rotationInterval)) % int64(len(sm.keys)))
+	}
+
+	if current == sm.current {
+		return
+	}
+
+	// The first key encrypts new tickets, all keys decrypt tickets.
+	keys := make([][sessionTicketKeySize]byte, 0, len(sm.keys))
+	keys = append(keys, sm.keys[current:]...)
+	keys = append(keys, sm.keys[:current]...)
+
+	sm.tlsConfig.SetSessionTicketKeys(keys)
+	sm.current = current
+}
+
+// parseSessionTicketKeys parses base64-encoded keys, one per line. Empty lines and comments starting with # are ignored.
+func parseSessionTicketKeys(content []byte) ([][sessionTicketKeySize]byte, error) {
+	var keys [][sessionTicketKeySize]byte
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rawKey, err := base64.StdEncoding.DecodeString(line)
+		if err != nil || len(rawKey) != sessionTicketKeySize {
+			return nil, fmt.Errorf("%w: line %d", errTLSSessionTicketKeyInvalid, lineNumber)
+		}
+
+		keys = append(keys, [sessionTicketKeySize]byte(rawKey))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		return nil, errTLSSessionTicketKeyNotFound
+	}
+
+	return keys, nil
+}
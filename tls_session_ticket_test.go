@@ -0,0 +1,237 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/relychan/goutils"
+)
+
+func TestParseSessionTicketKeys(t *testing.T) {
+	key := newTestSessionTicketKey(t)
+
+	tests := []struct {
+		name     string
+		content  string
+		expected int
+		wantErr  error
+	}{
+		{
+			name:     "keys with comments",
+			content:  "# current key\n" + key + "\n\n  " + newTestSessionTicketKey(t) + "  \n",
+			expected: 2,
+		},
+		{
+			name:    "invalid base64",
+			content: key + "\nnot-base64\n",
+			wantErr: errTLSSessionTicketKeyInvalid,
+		},
+		{
+			name:    "invalid key size",
+			content: base64.StdEncoding.EncodeToString([]byte("short")),
+			wantErr: errTLSSessionTicketKeyInvalid,
+		},
+		{
+			name:    "empty",
+			content: "# no key\n",
+			wantErr: errTLSSessionTicketKeyNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, err := parseSessionTicketKeys([]byte(tc.content))
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Errorf("expected %v, got %v", tc.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(keys) != tc.expected {
+				t.Errorf("expected %d keys, got %d", tc.expected, len(keys))
+			}
+		})
+	}
+}
+
+func TestSessionTicketKeyManager_Rotation(t *testing.T) {
+	t.Run("key file", func(t *testing.T) {
+		keyFile := writeTestSessionTicketKeys(t, filepath.Join(t.TempDir(), "ticket.keys"), 3)
+
+		manager, err := newSessionTicketKeyManager(&tls.Config{}, TLSConfig{
+			SessionTicketKeyFile:             keyFile,
+			SessionTicketKeyRotationInterval: goutils.Duration(time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("failed to create manager: %v", err)
+		}
+
+		epoch := time.Unix(0, 0)
+
+		for hours, expected := range []int{0, 1, 2, 0} {
+			manager.apply(epoch.Add(time.Duration(hours)*time.Hour + time.Minute))
+
+			if manager.current != expected {
+				t.Errorf("expected key %d after %d hours, got %d", expected, hours, manager.current)
+			}
+		}
+	})
+
+	t.Run("generated keys", func(t *testing.T) {
+		manager, err := newSessionTicketKeyManager(&tls.Config{}, TLSConfig{
+			SessionTicketKeyRotationInterval: goutils.Duration(time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("failed to create manager: %v", err)
+		}
+
+		for range 5 {
+			previous := manager.keys[0]
+
+			manager.tick(time.Now())
+
+			if manager.keys[0] == previous || manager.keys[1] != previous {
+				t.Fatal("expected a new key that encrypts new tickets")
+			}
+		}
+
+		if len(manager.keys) != 1+maxPreviousSessionTicketKeys {
+			t.Errorf("expected %d keys, got %d", 1+maxPreviousSessionTicketKeys, len(manager.keys))
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		manager, err := newSessionTicketKeyManager(&tls.Config{}, TLSConfig{})
+		if err != nil || manager != nil {
+			t.Errorf("expected no manager, got %v, %v", manager, err)
+		}
+	})
+}
+
+func TestServerSessionTicketResumption(t *testing.T) {
+	ca := newTestCertificateAuthority(t)
+	certFile, keyFile := ca.writeCertificate(t, ca.issue(t, "localhost", nil))
+	dir := t.TempDir()
+	sharedKeyFile := writeTestSessionTicketKeys(t, filepath.Join(dir, "shared.keys"), 1)
+	otherKeyFile := writeTestSessionTicketKeys(t, filepath.Join(dir, "other.keys"), 1)
+
+	newServer := func(ticketKeyFile string) *Server {
+		server := newTestServer(t, &ServerConfig{
+			TLSCertFile: certFile,
+			TLSKeyFile:  keyFile,
+			TLS:         &TLSConfig{SessionTicketKeyFile: ticketKeyFile},
+		})
+
+		if err := server.Start(context.Background()); err != nil {
+			t.Fatalf("failed to start server: %v", err)
+		}
+
+		return server
+	}
+
+	first := newServer(sharedKeyFile)
+	second := newServer(otherKeyFile)
+
+	request := func(server *Server, sessionCache tls.ClientSessionCache) bool {
+		client := &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					RootCAs:            ca.pool,
+					ServerName:         "localhost",
+					ClientSessionCache: sessionCache,
+				},
+				DisableKeepAlives: true,
+			},
+		}
+
+		resp, err := client.Get("https://" + server.Addr().String() + "/test")
+		if err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		return resp.TLS.DidResume
+	}
+
+	sessionCache := tls.NewLRUClientSessionCache(1)
+	request(first, sessionCache)
+
+	if request(second, sessionCache) {
+		t.Error("expected the session not to resume with different keys")
+	}
+
+	sessionCache = tls.NewLRUClientSessionCache(1)
+	request(first, sessionCache)
+
+	content, err := os.ReadFile(sharedKeyFile)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	if err := os.WriteFile(otherKeyFile, content, 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	second.sessionTicketKeys.tick(time.Now())
+
+	if !request(second, sessionCache) {
+		t.Error("expected the session to resume on another server with the reloaded shared keys")
+	}
+}
+
+func newTestSessionTicketKey(t *testing.T) string {
+	t.Helper()
+
+	key := make([]byte, sessionTicketKeySize)
+
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func writeTestSessionTicketKeys(t *testing.T, keyFile string, count int) string {
+	t.Helper()
+
+	keys := make([]string, count)
+
+	for i := range keys {
+		keys[i] = newTestSessionTicketKey(t)
+	}
+
+	if err := os.WriteFile(keyFile, []byte(strings.Join(keys, "\n")), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	return keyFile
+}
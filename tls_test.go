@@ -0,0 +1,413 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttps
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/relychan/gohttps/middlewares"
+)
+
+func TestTLSConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  TLSConfig
+		wantErr error
+	}{
+		{
+			name:   "empty",
+			config: TLSConfig{},
+		},
+		{
+			name:   "client CA defaults to require and verify",
+			config: TLSConfig{ClientCAFile: "ca.pem", AllowedClientSANs: []string{"*.example.com"}},
+		},
+		{
+			name:    "invalid client auth",
+			config:  TLSConfig{ClientAuth: "always"},
+			wantErr: errTLSClientAuthInvalid,
+		},
+		{
+			name:    "verify without client CA",
+			config:  TLSConfig{ClientAuth: TLSClientAuthVerifyIfGiven},
+			wantErr: errTLSClientCAFileRequired,
+		},
+		{
+			name: "allowlist without verification",
+			config: TLSConfig{
+				ClientAuth:             TLSClientAuthRequire,
+				AllowedClientSPIFFEIDs: []string{"spiffe://example.org/service"},
+			},
+			wantErr: errTLSAllowlistRequiresVerify,
+		},
+		{
+			name: "invalid SPIFFE ID",
+			config: TLSConfig{
+				ClientCAFile:           "ca.pem",
+				AllowedClientSPIFFEIDs: []string{"https://example.org/service"},
+			},
+			wantErr: errTLSSPIFFEIDInvalid,
+		},
+		{
+			name:    "certificate without key file",
+			config:  TLSConfig{Certificates: []TLSCertificateConfig{{CertFile: "cert.pem"}}},
+			wantErr: errTLSCertificateRequired,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.wantErr == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if !errors.Is(err, tc.wantErr) {
+				t.Errorf("expected %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMatchDNSName(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{pattern: "api.example.com", name: "api.example.com", expected: true},
+		{pattern: "API.example.com.", name: "api.example.com", expected: true},
+		{pattern: "api.example.com", name: "web.example.com", expected: false},
+		{pattern: "*.example.com", name: "api.example.com", expected: true},
+		{pattern: "*.example.com", name: "example.com", expected: false},
+		{pattern: "*.example.com", name: "v1.api.example.com", expected: false},
+	}
+
+	for _, tc := range tests {
+		if result := matchDNSName(tc.pattern, tc.name); result != tc.expected {
+			t.Errorf("matchDNSName(%s, %s): expected %t, got %t", tc.pattern, tc.name, tc.expected, result)
+		}
+	}
+}
+
+func TestServerMutualTLS(t *testing.T) {
+	ca := newTestCertificateAuthority(t)
+	serverCertFile, serverKeyFile := ca.writeCertificate(t, ca.issue(t, "localhost", nil))
+
+	config := &ServerConfig{
+		TLSCertFile: serverCertFile,
+		TLSKeyFile:  serverKeyFile,
+		TLS: &TLSConfig{
+			ClientCAFile:           ca.certFile,
+			AllowedClientSPIFFEIDs: []string{"spiffe://example.org/ns/default/*"},
+		},
+	}
+
+	router := NewRouter(config, slog.Default())
+	router.Get("/identity", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(middlewares.GetPeerIdentity(r.Context()).SPIFFEID))
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	server, err := NewServer(config, router, WithListener(listener))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = server.Shutdown(context.Background())
+	})
+
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+
+	requestURL := "https://" + server.Addr().String() + "/identity"
+
+	request := func(clientCert *tls.Certificate) (string, error) {
+		tlsConfig := &tls.Config{
+			RootCAs:    ca.pool,
+			ServerName: "localhost",
+		}
+
+		if clientCert != nil {
+			tlsConfig.Certificates = []tls.Certificate{*clientCert}
+		}
+
+		client := &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig:   tlsConfig,
+				DisableKeepAlives: true,
+			},
+		}
+
+		resp, err := client.Get(requestURL)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+
+		return string(body), err
+	}
+
+	t.Run("allowed SPIFFE ID", func(t *testing.T) {
+		spiffeID := "spiffe://example.org/ns/default/sa/client"
+		clientCert := ca.issue(t, "client", &x509.Certificate{
+			URIs: []*url.URL{{Scheme: "spiffe", Host: "example.org", Path: "/ns/default/sa/client"}},
+		})
+
+		body, err := request(&clientCert)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if body != spiffeID {
+			t.Errorf("expected the peer identity %s, got %s", spiffeID, body)
+		}
+	})
+
+	t.Run("rejects SPIFFE ID not in the allowlist", func(t *testing.T) {
+		clientCert := ca.issue(t, "client", &x509.Certificate{
+			URIs: []*url.URL{{Scheme: "spiffe", Host: "example.org", Path: "/ns/other/sa/client"}},
+		})
+
+		if _, err := request(&clientCert); err == nil {
+			t.Error("expected the handshake to fail")
+		}
+	})
+
+	t.Run("rejects clients without certificates", func(t *testing.T) {
+		if _, err := request(nil); err == nil {
+			t.Error("expected the handshake to fail")
+		}
+	})
+
+	t.Run("rejects certificates of other CAs", func(t *testing.T) {
+		clientCert := newTestCertificateAuthority(t).issue(t, "client", &x509.Certificate{
+			URIs: []*url.URL{{Scheme: "spiffe", Host: "example.org", Path: "/ns/default/sa/client"}},
+		})
+
+		if _, err := request(&clientCert); err == nil {
+			t.Error("expected the handshake to fail")
+		}
+	})
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	t.Run("requires certificate files", func(t *testing.T) {
+		_, err := newTLSConfig(&ServerConfig{TLS: &TLSConfig{}})
+		if !errors.Is(err, errTLSCertificateRequired) {
+			t.Errorf("expected errTLSCertificateRequired, got %v", err)
+		}
+	})
+
+	t.Run("requires both default certificate files", func(t *testing.T) {
+		_, err := newTLSConfig(&ServerConfig{
+			TLSCertFile: "cert.pem",
+			TLS:         &TLSConfig{CertificateDir: "certs"},
+		})
+		if !errors.Is(err, errTLSCertificateRequired) {
+			t.Errorf("expected errTLSCertificateRequired, got %v", err)
+		}
+	})
+
+	t.Run("certificate directory only", func(t *testing.T) {
+		tlsConfig, err := newTLSConfig(&ServerConfig{TLS: &TLSConfig{CertificateDir: "certs"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if tlsConfig == nil {
+			t.Error("expected TLS config, got nil")
+		}
+	})
+
+	t.Run("invalid client CA file", func(t *testing.T) {
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		if err := os.WriteFile(caFile, []byte("invalid"), 0o600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		_, err := newTLSConfig(&ServerConfig{
+			TLSCertFile: "cert.pem",
+			TLSKeyFile:  "key.pem",
+			TLS:         &TLSConfig{ClientCAFile: caFile},
+		})
+		if !errors.Is(err, errTLSClientCAInvalid) {
+			t.Errorf("expected errTLSClientCAInvalid, got %v", err)
+		}
+	})
+}
+
+// testCertificateAuthority issues certificates for tests.
+type testCertificateAuthority struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	pool     *x509.CertPool
+	certFile string
+}
+
+func newTestCertificateAuthority(t *testing.T) *testCertificateAuthority {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	certFile := filepath.Join(t.TempDir(), "ca.pem")
+
+	err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+
+	return &testCertificateAuthority{
+		cert:     cert,
+		key:      key,
+		pool:     pool,
+		certFile: certFile,
+	}
+}
+
+// issue creates a certificate for the common name that is valid as both server and client certificates.
+// The template overrides subject alternative names and the validity period if set.
+func (ca *testCertificateAuthority) issue(t *testing.T, commonName string, template *x509.Certificate) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	if template == nil {
+		template = &x509.Certificate{}
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatalf("failed to generate serial number: %v", err)
+	}
+
+	template.SerialNumber = serialNumber
+	template.Subject = pkix.Name{CommonName: commonName}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+
+	if template.NotBefore.IsZero() {
+		template.NotBefore = time.Now().Add(-time.Hour)
+	}
+
+	if template.NotAfter.IsZero() {
+		template.NotAfter = time.Now().Add(24 * time.Hour)
+	}
+
+	if len(template.DNSNames) == 0 && len(template.URIs) == 0 {
+		template.DNSNames = []string{commonName}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}
+}
+
+// writeCertificate writes the certificate and key to PEM files in a temporary directory.
+func (ca *testCertificateAuthority) writeCertificate(t *testing.T, cert tls.Certificate) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	writeTestKeyPair(t, cert, certFile, keyFile)
+
+	return certFile, keyFile
+}
+
+func writeTestKeyPair(t *testing.T, cert tls.Certificate, certFile string, keyFile string) {
+	t.Helper()
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+}
@@ -16,29 +16,52 @@ package gohttps
 
 import (
 	"errors"
+	"strconv"
+	"time"
 
+	"github.com/relychan/gohttps/listeners"
 	"github.com/relychan/gohttps/middlewares"
 	"github.com/relychan/goutils"
 )
 
 const (
-	pathMetrics = "/metrics"
-	pathHealthz = "/healthz"
+	pathMetrics  = "/metrics"
+	pathHealthz  = "/healthz"
+	pathLivez    = "/livez"
+	pathReadyz   = "/readyz"
+	pathStartupz = "/startupz"
 )
 
 const (
 	kilobyte = 1024
+
+	defaultShutdownTimeout = 30 * time.Second
+	defaultUpgradeTimeout  = time.Minute
+
+	// The name of the Prometheus server listener when it is handed off to another process.
+	prometheusListenerName = "prometheus"
 )
 
 var (
 	errPrometheusInvalidPort = errors.New("invalid prometheus port")
 	errServerConfigRequired  = errors.New("server config is required")
+	errShutdownTimeout       = errors.New("graceful shutdown timed out")
+	errServerAlreadyStarted  = errors.New("server is already started")
+	errServerAlreadyUpgraded = errors.New("server is already upgraded")
 )
 
 // ServerConfig holds information of required environment variables.
 type ServerConfig struct {
 	// The port where the server is listening to.
 	Port int `env:"PORT" json:"port,omitempty" yaml:"port,omitempty" jsonschema:"default=8080,minimum=1000,maximum=65535"`
+	// The list of addresses that the server listens to, e.g. 127.0.0.1:8080, [::1]:8080 or unix:///run/app.sock.
+	// All addresses serve the same router concurrently. If empty, the server listens to all interfaces of the port.
+	Addresses []ListenAddress `json:"addresses,omitempty" yaml:"addresses,omitempty"`
+	// Serve on listeners inherited by systemd socket activation (LISTEN_FDS) instead of listening to the addresses.
+	// Inherited file descriptors are matched with addresses by names (LISTEN_FDNAMES). Default is true.
+	SocketActivation *bool `env:"SERVER_SOCKET_ACTIVATION" json:"socketActivation,omitempty" yaml:"socketActivation,omitempty"`
+	// The socket options of TCP listeners and accepted connections, e.g. keep-alive, TCP_NODELAY and SO_REUSEPORT.
+	Listener *ListenerConfig `json:"listener,omitempty" yaml:"listener,omitempty"`
 	// Level of the logger.
 	LogLevel string `env:"LOG_LEVEL" json:"logLevel,omitempty" yaml:"logLevel,omitempty" jsonschema:"enum=INFO,enum=DEBUG,enum=WARN,enum=ERROR,default=INFO"`
 	// Default level which the server uses to compress response bodies.
@@ -60,20 +83,66 @@ type ServerConfig struct {
 	// If zero, the value of ReadTimeout is used.
 	// If negative, or if zero and ReadTimeout is zero or negative, there is no timeout.
 	IdleTimeout goutils.Duration `env:"SERVER_IDLE_TIMEOUT" json:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty"`
+	// The minimum data rates of request bodies and responses to close connections of slow clients,
+	// so large transfers do not require long read and write timeouts.
+	MinDataRate *middlewares.MinDataRateConfig `json:"minDataRate,omitempty" yaml:"minDataRate,omitempty"`
 	// The maximum number of bytes the server will read parsing the request header's keys and values, including the request line.
 	// It does not limit the size of the request body. If zero, DefaultMaxHeaderBytes is used.
 	MaxHeaderKilobytes int `env:"SERVER_MAX_HEADER_KILOBYTES" json:"maxHeaderKilobytes,omitempty" yaml:"maxHeaderKilobytes,omitempty"`
 	// The maximum number of bytes the server will read parsing the request body.
 	// A zero or negative value means there will be no limit.
 	MaxBodyKilobytes int `env:"SERVER_MAX_BODY_KILOBYTES" json:"maxBodyKilobytes,omitempty" yaml:"maxBodyKilobytes,omitempty"`
+	// The maximum duration to wait for in-flight requests to complete when the server is shutting down.
+	// Connections that are still open once the deadline passes are forcibly closed.
+	// If zero or negative, the default value is 30 seconds.
+	ShutdownTimeout goutils.Duration `env:"SERVER_SHUTDOWN_TIMEOUT" json:"shutdownTimeout,omitempty" yaml:"shutdownTimeout,omitempty"`
+	// The duration to keep serving traffic after receiving the quit signal before the graceful shutdown starts.
+	// During this period the health check endpoint responds with 503 Service Unavailable,
+	// so load balancers and Kubernetes stop routing new requests to the server.
+	PreStopDelay goutils.Duration `env:"SERVER_PRE_STOP_DELAY" json:"preStopDelay,omitempty" yaml:"preStopDelay,omitempty"`
+	// Hand off listeners to a new process of the same executable on the SIGUSR2 signal for zero-downtime binary upgrades.
+	// The current process drains in-flight requests once the new process is ready.
+	UpgradeOnSignal bool `env:"SERVER_UPGRADE_ON_SIGNAL" json:"upgradeOnSignal,omitempty" yaml:"upgradeOnSignal,omitempty"`
+	// The maximum duration to wait for the new process to be ready during the upgrade. Default is 1 minute.
+	UpgradeTimeout goutils.Duration `env:"SERVER_UPGRADE_TIMEOUT" json:"upgradeTimeout,omitempty" yaml:"upgradeTimeout,omitempty"`
+	// The HTTP protocols that the server accepts: http1, http2 over TLS and h2c (HTTP/2 over cleartext connections).
+	// Default is http1 and http2.
+	Protocols []HTTPProtocol `env:"SERVER_PROTOCOLS" json:"protocols,omitempty" yaml:"protocols,omitempty" jsonschema:"enum=http1,enum=http2,enum=h2c"`
+	// The HTTP/2 configuration of the server, e.g. the maximum number of concurrent streams.
+	HTTP2 *HTTP2Config `json:"http2,omitempty" yaml:"http2,omitempty"`
+	// The HTTP/3 configuration of the server to serve requests over QUIC on UDP next to the TCP listeners.
+	HTTP3 *HTTP3Config `json:"http3,omitempty" yaml:"http3,omitempty"`
 	// The TLS certificate file to enable TLS connections.
 	TLSCertFile string `env:"SERVER_TLS_CERT_FILE" json:"tlsCertFile,omitempty" yaml:"tlsCertFile,omitempty"`
 	// The TLS key file to enable TLS connections.
 	TLSKeyFile string `env:"SERVER_TLS_KEY_FILE" json:"tlsKeyFile,omitempty" yaml:"tlsKeyFile,omitempty"`
+	// The TLS configuration of the server, e.g. mutual TLS, certificate reloading and SNI-based certificates.
+	// Require the TLS certificate and key files, or certificates of the TLS configuration.
+	TLS *TLSConfig `json:"tls,omitempty" yaml:"tls,omitempty"`
+	// Serve HTTPS with a self-signed development certificate if no certificate files are configured.
+	DevTLS *DevTLSConfig `json:"devTls,omitempty" yaml:"devTls,omitempty"`
+	// Indicate that the server runs in production. Development features, e.g. development TLS, are refused.
+	Production bool `env:"SERVER_PRODUCTION" json:"production,omitempty" yaml:"production,omitempty"`
 	// The configuration container to setup the CORS middleware.
 	CORS *CORSConfig `json:"cors,omitempty" yaml:"cors,omitempty"`
 	// The configuration container to setup the client IP middleware.
 	ClientIP *middlewares.ClientIPConfig `json:"clientIp,omitempty" yaml:"clientIp,omitempty"`
+	// The configuration of request IDs that are read from or generated for every request and echoed in responses.
+	RequestID *middlewares.RequestIDConfig `json:"requestId,omitempty" yaml:"requestId,omitempty"`
+	// The configuration of the OpenTelemetry tracing of HTTP requests, e.g. the sample ratio.
+	Tracing *middlewares.TracingConfig `json:"tracing,omitempty" yaml:"tracing,omitempty"`
+	// The configuration of the structured access logs of HTTP requests, e.g. the fields and sampling.
+	AccessLog *middlewares.AccessLogConfig `json:"accessLog,omitempty" yaml:"accessLog,omitempty"`
+	// The configuration of the RED metrics of HTTP requests, e.g. the request duration by route pattern.
+	RequestMetrics *middlewares.RequestMetricsConfig `json:"requestMetrics,omitempty" yaml:"requestMetrics,omitempty"`
+	// The configuration of the PROXY protocol that rewrites the remote address of connections from load balancers
+	// to the address of the client. Disabled if empty.
+	ProxyProtocol *listeners.ProxyProtocolConfig `json:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty"`
+	// The limits of concurrent connections across all listeners and per remote IP. Unlimited if empty.
+	ConnectionLimit *listeners.ConnectionLimitConfig `json:"connectionLimit,omitempty" yaml:"connectionLimit,omitempty"`
+	// The path of the admin endpoint that lists open connections for debugging, e.g. /debug/connections.
+	// Disabled if empty. The endpoint exposes addresses of clients, so restrict access to it.
+	ConnectionsEndpoint string `env:"SERVER_CONNECTIONS_ENDPOINT" json:"connectionsEndpoint,omitempty" yaml:"connectionsEndpoint,omitempty"`
 }
 
 // GetPort returns the port of server. Default is 8080.
@@ -85,6 +154,49 @@ func (sc ServerConfig) GetPort() int {
 	return 8080
 }
 
+// GetListenAddresses returns the addresses that the server listens to. Default is all interfaces of the port.
+func (sc ServerConfig) GetListenAddresses() []ListenAddress {
+	if len(sc.Addresses) > 0 {
+		return sc.Addresses
+	}
+
+	return []ListenAddress{
+		{Address: ":" + strconv.Itoa(sc.GetPort())},
+	}
+}
+
+// IsSocketActivationEnabled checks if the server inherits listeners from socket activation. Default is true.
+func (sc ServerConfig) IsSocketActivationEnabled() bool {
+	return sc.SocketActivation == nil || *sc.SocketActivation
+}
+
+// GetListenerConfig returns the socket options of listeners. Default is the socket options of Go.
+func (sc ServerConfig) GetListenerConfig() ListenerConfig {
+	if sc.Listener != nil {
+		return *sc.Listener
+	}
+
+	return ListenerConfig{}
+}
+
+// GetShutdownTimeout returns the graceful shutdown timeout. Default is 30 seconds.
+func (sc ServerConfig) GetShutdownTimeout() time.Duration {
+	if sc.ShutdownTimeout > 0 {
+		return time.Duration(sc.ShutdownTimeout)
+	}
+
+	return defaultShutdownTimeout
+}
+
+// GetUpgradeTimeout returns the maximum duration to wait for the new process during the upgrade. Default is 1 minute.
+func (sc ServerConfig) GetUpgradeTimeout() time.Duration {
+	if sc.UpgradeTimeout > 0 {
+		return time.Duration(sc.UpgradeTimeout)
+	}
+
+	return defaultUpgradeTimeout
+}
+
 // GetLogLevel returns the log level. Default is INFO.
 func (sc ServerConfig) GetLogLevel() string {
 	if sc.LogLevel != "" {
@@ -0,0 +1,36 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package gohttps
+
+import (
+	"sync"
+	"syscall"
+)
+
+// The file mode creation mask is process-wide, so changes are serialized.
+var umaskMu sync.Mutex
+
+// withUmask runs the function with the file mode creation mask, then restores the previous mask.
+func withUmask(mask int, fn func() error) error {
+	umaskMu.Lock()
+	defer umaskMu.Unlock()
+
+	previous := syscall.Umask(mask)
+	defer syscall.Umask(previous)
+
+	return fn()
+}
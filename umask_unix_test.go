@@ -0,0 +1,61 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package gohttps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithUmask(t *testing.T) {
+	dir := t.TempDir()
+
+	createFile := func(name string) os.FileMode {
+		t.Helper()
+
+		path := filepath.Join(dir, name)
+
+		if err := os.WriteFile(path, nil, 0o666); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("failed to stat file: %v", err)
+		}
+
+		return info.Mode().Perm()
+	}
+
+	before := createFile("before")
+
+	err := withUmask(0o177, func() error {
+		if mode := createFile("restricted"); mode != 0o600 {
+			t.Errorf("expected mode 0600, got %o", mode)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if after := createFile("after"); after != before {
+		t.Errorf("expected the previous umask to be restored with mode %o, got %o", before, after)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !unix
+
+package gohttps
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+var errUpgradeNotSupported = errors.New("upgrade is not supported on this platform")
+
+// Upgrade is not supported on this platform.
+func (s *Server) Upgrade(_ context.Context) error {
+	return errUpgradeNotSupported
+}
+
+// inheritListeners returns listeners and packet conns passed by the systemd socket activation protocol.
+func inheritListeners(config *ServerConfig) ([]namedListener, []namedPacketConn, *os.File, error) {
+	if !config.IsSocketActivationEnabled() {
+		return nil, nil, nil, nil
+	}
+
+	listeners, packetConns, err := inheritSystemdListeners()
+
+	return listeners, packetConns, nil, err
+}
+
+func notifyUpgradeReady(readyFile *os.File) {
+	_ = readyFile.Close()
+}
+
+// notifyUpgradeSignal returns a nil channel because upgrade signals are not supported on this platform.
+func notifyUpgradeSignal() (<-chan os.Signal, func()) {
+	return nil, func() {}
+}
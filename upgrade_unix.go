@@ -0,0 +1,326 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package gohttps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	envUpgradeParentPID = "GOHTTPS_UPGRADE_PARENT_PID"
+	envUpgradeReadyFD   = "GOHTTPS_UPGRADE_READY_FD"
+)
+
+var (
+	errUpgradeListenerNotSupported = errors.New("listener does not support file descriptor handoff")
+	errUpgradeChildExited          = errors.New("the new process exited before it was ready")
+	errUpgradeTimeout              = errors.New("timed out waiting for the new process to be ready")
+)
+
+// Upgrade starts a new process of the same executable and hands off the listening sockets to it
+// for zero-downtime binary upgrades. It returns once the new process is serving requests.
+// The caller should then call Shutdown to drain in-flight requests of the current process.
+// The current process keeps serving if the upgrade fails.
+func (s *Server) Upgrade(ctx context.Context) error {
+	s.upgradeMu.Lock()
+	defer s.upgradeMu.Unlock()
+
+	var http3Conn net.PacketConn
+
+	s.mu.Lock()
+	started := s.started
+	listeners := slices.Clone(s.listeners)
+
+	if s.http3 != nil {
+		http3Conn = s.http3.conn
+	}
+
+	s.mu.Unlock()
+
+	if !started {
+		return errServerNotStarted
+	}
+
+	if s.upgraded {
+		return errServerAlreadyUpgraded
+	}
+
+	if s.promListener != nil {
+		listeners = append(listeners, namedListener{
+			name:     prometheusListenerName,
+			listener: s.promListener,
+		})
+	}
+
+	files := make([]*os.File, 0, len(listeners)+2)
+	names := make([]string, 0, len(listeners)+1)
+
+	defer func() {
+		for _, file := range files {
+			_ = file.Close()
+		}
+	}()
+
+	for _, nl := range listeners {
+		filer, ok := nl.listener.(interface{ File() (*os.File, error) })
+		if !ok {
+			return fmt.Errorf("%w: %s", errUpgradeListenerNotSupported, nl.listener.Addr())
+		}
+
+		file, err := filer.File()
+		if err != nil {
+			return err
+		}
+
+		files = append(files, file)
+		names = append(names, nl.name)
+	}
+
+	// The UDP socket of HTTP/3 is handed off as well, so the new process does not bind the port again.
+	if http3Conn != nil {
+		filer, ok := http3Conn.(interface{ File() (*os.File, error) })
+		if !ok {
+			return fmt.Errorf("%w: %s", errUpgradeListenerNotSupported, http3Conn.LocalAddr())
+		}
+
+		file, err := filer.File()
+		if err != nil {
+			return err
+		}
+
+		files = append(files, file)
+		names = append(names, http3ListenerName)
+	}
+
+	readyReader, readyWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	defer readyReader.Close()
+
+	files = append(files, readyWriter)
+
+	process, err := s.startUpgradeProcess(files, names)
+	if err != nil {
+		return err
+	}
+
+	// Close the write end in this process so the read end returns EOF if the new process exits.
+	_ = readyWriter.Close()
+	files = files[:len(files)-1]
+
+	slog.Info("started the new process, waiting for it to be ready", slog.Int("pid", process.Pid))
+
+	err = waitUpgradeReady(ctx, process, readyReader, s.config.GetUpgradeTimeout())
+	if err != nil {
+		return err
+	}
+
+	slog.Info("the new process is ready", slog.Int("pid", process.Pid))
+
+	// The socket files of unix listeners are now owned by the new process.
+	for _, nl := range listeners {
+		if ul, ok := nl.listener.(*net.UnixListener); ok {
+			ul.SetUnlinkOnClose(false)
+		}
+	}
+
+	s.upgraded = true
+
+	return nil
+}
+
+// startUpgradeProcess starts a new process of the same executable that inherits the files from the 3rd file descriptor.
+// The process is forked with raw file descriptors because os.File.Fd, which is used by os/exec,
+// switches the shared sockets to blocking mode and would stall the accept loops of the current process.
+func (s *Server) startUpgradeProcess(files []*os.File, names []string) (*os.Process, error) {
+	executable, err := exec.LookPath(os.Args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	args := os.Args[1:]
+	if s.options.upgradeArgs != nil {
+		args = s.options.upgradeArgs
+	}
+
+	fds := []uintptr{os.Stdin.Fd(), os.Stdout.Fd(), os.Stderr.Fd()}
+
+	for _, file := range files {
+		rawConn, err := file.SyscallConn()
+		if err != nil {
+			return nil, err
+		}
+
+		err = rawConn.Control(func(fd uintptr) {
+			fds = append(fds, fd)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	env := append(
+		os.Environ(),
+		envUpgradeParentPID+"="+strconv.Itoa(os.Getpid()),
+		envUpgradeReadyFD+"="+strconv.Itoa(listenFDsStart+len(names)),
+		envListenFDs+"="+strconv.Itoa(len(names)),
+		envListenFDNames+"="+strings.Join(names, ":"),
+	)
+
+	pid, err := syscall.ForkExec(executable, append([]string{os.Args[0]}, args...), &syscall.ProcAttr{
+		Env:   env,
+		Files: fds,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return os.FindProcess(pid)
+}
+
+func waitUpgradeReady(
+	ctx context.Context,
+	process *os.Process,
+	readyReader *os.File,
+	timeout time.Duration,
+) error {
+	readyCh := make(chan bool, 1)
+
+	go func() {
+		buf := make([]byte, 1)
+		n, _ := readyReader.Read(buf)
+		readyCh <- n > 0
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var err error
+
+	select {
+	case ready := <-readyCh:
+		if ready {
+			// Reap the new process when it exits.
+			go func() {
+				_, _ = process.Wait()
+			}()
+
+			return nil
+		}
+
+		err = errUpgradeChildExited
+	case <-timer.C:
+		err = errUpgradeTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	_ = process.Kill()
+	_, _ = process.Wait()
+
+	return err
+}
+
+// inheritUpgradeListeners returns listeners and packet conns handed off by the parent process during an upgrade
+// and the pipe to notify the parent process once the server is ready.
+func inheritUpgradeListeners() ([]namedListener, []namedPacketConn, *os.File, error) {
+	rawParentPID, ok := os.LookupEnv(envUpgradeParentPID)
+	if !ok {
+		return nil, nil, nil, nil
+	}
+
+	rawFDs := os.Getenv(envListenFDs)
+	rawNames := os.Getenv(envListenFDNames)
+	rawReadyFD := os.Getenv(envUpgradeReadyFD)
+
+	unsetEnvs(envUpgradeParentPID, envUpgradeReadyFD, envListenPID, envListenFDs, envListenFDNames)
+
+	// The file descriptors are intended for another process.
+	if rawParentPID != strconv.Itoa(os.Getppid()) {
+		return nil, nil, nil, nil
+	}
+
+	readyFD, err := strconv.Atoi(rawReadyFD)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid %s environment variable: %s", envUpgradeReadyFD, rawReadyFD)
+	}
+
+	syscall.CloseOnExec(readyFD)
+
+	readyFile := os.NewFile(uintptr(readyFD), "upgrade-ready")
+
+	listeners, packetConns, err := listenersFromFDs(rawFDs, rawNames)
+	if err != nil {
+		_ = readyFile.Close()
+
+		return nil, nil, nil, err
+	}
+
+	return listeners, packetConns, readyFile, nil
+}
+
+// inheritListeners returns listeners and packet conns handed off by the parent process during an upgrade,
+// or passed by the systemd socket activation protocol.
+func inheritListeners(config *ServerConfig) ([]namedListener, []namedPacketConn, *os.File, error) {
+	listeners, packetConns, readyFile, err := inheritUpgradeListeners()
+	if err != nil || readyFile != nil {
+		return listeners, packetConns, readyFile, err
+	}
+
+	if !config.IsSocketActivationEnabled() {
+		return nil, nil, nil, nil
+	}
+
+	listeners, packetConns, err = inheritSystemdListeners()
+
+	return listeners, packetConns, nil, err
+}
+
+// notifyUpgradeReady notifies the parent process that the server is ready.
+func notifyUpgradeReady(readyFile *os.File) {
+	_, err := readyFile.Write([]byte{1})
+	if err != nil {
+		slog.Warn("failed to notify the parent process: " + err.Error())
+	}
+
+	_ = readyFile.Close()
+}
+
+// notifyUpgradeSignal relays the SIGUSR2 signal to trigger upgrades.
+func notifyUpgradeSignal() (<-chan os.Signal, func()) {
+	signals := make(chan os.Signal, 1)
+
+	signal.Notify(signals, syscall.SIGUSR2)
+
+	return signals, func() {
+		signal.Stop(signals)
+	}
+}
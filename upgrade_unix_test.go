@@ -0,0 +1,241 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package gohttps
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+const (
+	envTestUpgradeChild       = "GOHTTPS_TEST_UPGRADE_CHILD"
+	envTestUpgradeChildHTTP3  = "GOHTTPS_TEST_UPGRADE_CHILD_HTTP3"
+	envTestUpgradeChildCACert = "GOHTTPS_TEST_UPGRADE_CHILD_CA_CERT"
+)
+
+func TestServerUpgrade(t *testing.T) {
+	t.Run("not started", func(t *testing.T) {
+		server := newTestServer(t, &ServerConfig{})
+
+		if err := server.Upgrade(context.Background()); !errors.Is(err, errServerNotStarted) {
+			t.Errorf("expected errServerNotStarted, got %v", err)
+		}
+	})
+
+	t.Run("child exits before ready", func(t *testing.T) {
+		// The child test is skipped without the address of the inherited listener.
+		server := newTestServer(t, &ServerConfig{
+			Addresses: []ListenAddress{
+				{Name: "http", Address: "127.0.0.1:0"},
+			},
+		}, WithUpgradeArgs("-test.run=^TestServerUpgradeChild$"))
+
+		if err := server.Start(context.Background()); err != nil {
+			t.Fatalf("failed to start server: %v", err)
+		}
+
+		if err := server.Upgrade(context.Background()); !errors.Is(err, errUpgradeChildExited) {
+			t.Errorf("expected errUpgradeChildExited, got %v", err)
+		}
+
+		// The current process keeps serving if the upgrade fails.
+		waitForStatus(t, "http://"+server.Addr().String()+"/test", http.StatusOK)
+	})
+
+	t.Run("hand off listeners", func(t *testing.T) {
+		server := newTestServer(t, &ServerConfig{
+			Addresses: []ListenAddress{
+				{Name: "http", Address: "127.0.0.1:0"},
+			},
+		}, WithUpgradeArgs("-test.run=^TestServerUpgradeChild$"))
+
+		if err := server.Start(context.Background()); err != nil {
+			t.Fatalf("failed to start server: %v", err)
+		}
+
+		baseURL := "http://" + server.Addr().String()
+
+		t.Setenv(envTestUpgradeChild, server.Addr().String())
+
+		if err := server.Upgrade(context.Background()); err != nil {
+			t.Fatalf("failed to upgrade server: %v", err)
+		}
+
+		if err := server.Upgrade(context.Background()); !errors.Is(err, errServerAlreadyUpgraded) {
+			t.Errorf("expected errServerAlreadyUpgraded, got %v", err)
+		}
+
+		if err := server.Shutdown(context.Background()); err != nil {
+			t.Fatalf("failed to shutdown server: %v", err)
+		}
+
+		// The new process keeps serving on the same address.
+		resp, err := testHTTPClient.Get(baseURL + "/pid")
+		if err != nil {
+			t.Fatalf("failed to request the new process: %v", err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if pid, err := strconv.Atoi(string(body)); err != nil || pid == os.Getpid() {
+			t.Errorf("expected the response from the new process, got %s", body)
+		}
+
+		resp, err = testHTTPClient.Get(baseURL + "/quit")
+		if err == nil {
+			resp.Body.Close()
+		}
+	})
+
+	t.Run("hand off the HTTP/3 socket", func(t *testing.T) {
+		caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+
+		server := newTestServer(t, newTestUpgradeHTTP3Config(caCertFile), WithUpgradeArgs("-test.run=^TestServerUpgradeChild$"))
+
+		if err := server.Start(context.Background()); err != nil {
+			t.Fatalf("failed to start server: %v", err)
+		}
+
+		h3URL := "https://" + server.HTTP3Addr().String()
+
+		t.Setenv(envTestUpgradeChild, server.Addr().String())
+		t.Setenv(envTestUpgradeChildHTTP3, server.HTTP3Addr().String())
+		t.Setenv(envTestUpgradeChildCACert, caCertFile)
+
+		if err := server.Upgrade(context.Background()); err != nil {
+			t.Fatalf("failed to upgrade server: %v", err)
+		}
+
+		if err := server.Shutdown(context.Background()); err != nil {
+			t.Fatalf("failed to shutdown server: %v", err)
+		}
+
+		// The new process writes its own development CA.
+		h3Transport := &http3.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:    loadTestCertPool(t, caCertFile),
+			ServerName: "localhost",
+		}}
+		defer h3Transport.Close()
+
+		h3Client := &http.Client{Transport: h3Transport, Timeout: 5 * time.Second}
+
+		resp, err := h3Client.Get(h3URL + "/pid")
+		if err != nil {
+			t.Fatalf("failed to request the new process over HTTP/3: %v", err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if pid, err := strconv.Atoi(string(body)); err != nil || pid == os.Getpid() {
+			t.Errorf("expected the response from the new process, got %s", body)
+		}
+
+		resp, err = h3Client.Get(h3URL + "/quit")
+		if err == nil {
+			resp.Body.Close()
+		}
+	})
+}
+
+func newTestUpgradeHTTP3Config(caCertFile string) *ServerConfig {
+	return &ServerConfig{
+		Addresses: []ListenAddress{
+			{Name: "http", Address: "127.0.0.1:0"},
+		},
+		DevTLS: &DevTLSConfig{
+			Enabled:    true,
+			CACertFile: caCertFile,
+		},
+		HTTP3: &HTTP3Config{
+			Enabled: true,
+			Address: "127.0.0.1:0",
+		},
+	}
+}
+
+// TestServerUpgradeChild serves requests on the listeners handed off by TestServerUpgrade.
+func TestServerUpgradeChild(t *testing.T) {
+	inheritedAddress := os.Getenv(envTestUpgradeChild)
+	if inheritedAddress == "" {
+		t.Skip("run by TestServerUpgrade")
+	}
+
+	var quitOnce sync.Once
+
+	quit := make(chan struct{})
+	config := &ServerConfig{
+		Addresses: []ListenAddress{
+			{Name: "http", Address: "127.0.0.1:0"},
+		},
+	}
+
+	inheritedHTTP3Address := os.Getenv(envTestUpgradeChildHTTP3)
+	if inheritedHTTP3Address != "" {
+		config = newTestUpgradeHTTP3Config(os.Getenv(envTestUpgradeChildCACert))
+	}
+
+	router := NewRouter(config, slog.Default())
+	router.Get("/pid", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strconv.Itoa(os.Getpid())))
+	})
+	router.Get("/quit", func(w http.ResponseWriter, r *http.Request) {
+		quitOnce.Do(func() {
+			close(quit)
+		})
+	})
+
+	server, err := NewServer(config, router)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+
+	if addrs := server.Addrs(); len(addrs) != 1 || addrs[0].String() != inheritedAddress {
+		t.Fatalf("expected the inherited listener %s, got %v", inheritedAddress, addrs)
+	}
+
+	if inheritedHTTP3Address != "" && server.HTTP3Addr().String() != inheritedHTTP3Address {
+		t.Fatalf("expected the inherited HTTP/3 socket %s, got %s", inheritedHTTP3Address, server.HTTP3Addr())
+	}
+
+	select {
+	case <-quit:
+	case <-time.After(10 * time.Second):
+		t.Error("timed out waiting for the quit request")
+	}
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Errorf("failed to shutdown server: %v", err)
+	}
+}